@@ -0,0 +1,222 @@
+package bundle
+
+import (
+	"errors"
+	"time"
+
+	"github.com/eaigner/igi/hash"
+	"github.com/eaigner/igi/node"
+	"github.com/eaigner/igi/signing"
+	"github.com/eaigner/igi/trinary"
+)
+
+// insecureTryte is the normalized bundle hash value that reveals a whole key segment when signed.
+const insecureTryte = 13
+
+var (
+	errNoTransfers         = errors.New("bundle has no transfers")
+	errNegativeValue       = errors.New("transfer value must not be negative")
+	errInvalidTag          = errors.New("tag must consist of at most 27 trytes")
+	errInvalidSecurity     = errors.New("input security level must be between 1 and 3")
+	errInvalidInputBalance = errors.New("input balance must be positive")
+	errInsufficientBalance = errors.New("input balance is lower than the transferred value")
+	errMissingRemainder    = errors.New("remainder address required for the unspent input balance")
+)
+
+// Transfer is an output of a bundle.
+type Transfer struct {
+	Address hash.Hash
+	Value   int64
+	Tag     string // trytes, padded to 27 trytes
+	Message string // text, split across as many transactions as needed
+}
+
+// Input is an address whose whole balance is spent by the bundle.
+type Input struct {
+	Address  hash.Hash
+	Balance  int64
+	Security int // security level of the address, the signature takes one transaction per level
+}
+
+// Builder assembles a bundle from transfers and inputs.
+// Transfers come first, followed by the inputs and the remainder.
+type Builder struct {
+	Transfers []Transfer
+	Inputs    []Input
+	Remainder hash.Hash // receives the input balance that is not transferred
+	Tag       string    // tag of input and remainder transactions
+	Timestamp time.Time // defaults to the current time
+}
+
+// Build creates the transactions of the bundle and computes the bundle hash.
+// The signature fragments of inputs, trunk, branch and nonce are left empty for signing and PoW.
+func (b *Builder) Build() (Bundle, error) {
+	if len(b.Transfers) == 0 {
+		return nil, errNoTransfers
+	}
+
+	ts := b.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+
+	var (
+		txs     Bundle
+		outputs int64
+		inputs  int64
+	)
+
+	for _, t := range b.Transfers {
+		if t.Value < 0 {
+			return nil, errNegativeValue
+		}
+		outputs += t.Value
+		if outputs > TotalSupply {
+			return nil, errInvalidValue
+		}
+
+		fragments, err := messageFragments(t.Message)
+		if err != nil {
+			return nil, err
+		}
+		for i, fragment := range fragments {
+			m, err := newTx(t.Address, t.Tag, ts)
+			if err != nil {
+				return nil, err
+			}
+			if i == 0 {
+				m.Value = t.Value
+			}
+			m.SignatureMessageFragment = fragment
+			txs = append(txs, m)
+		}
+	}
+
+	for _, in := range b.Inputs {
+		if in.Security < 1 || in.Security > signing.MaxSecurityLevel {
+			return nil, errInvalidSecurity
+		}
+		if in.Balance <= 0 {
+			return nil, errInvalidInputBalance
+		}
+		inputs += in.Balance
+		if inputs > TotalSupply {
+			return nil, errInvalidValue
+		}
+
+		for i := 0; i < in.Security; i++ {
+			m, err := newTx(in.Address, b.Tag, ts)
+			if err != nil {
+				return nil, err
+			}
+			if i == 0 {
+				m.Value = -in.Balance
+			}
+			txs = append(txs, m)
+		}
+	}
+
+	if inputs < outputs {
+		return nil, errInsufficientBalance
+	}
+	if remainder := inputs - outputs; remainder > 0 {
+		if b.Remainder.Zero() {
+			return nil, errMissingRemainder
+		}
+		m, err := newTx(b.Remainder, b.Tag, ts)
+		if err != nil {
+			return nil, err
+		}
+		m.Value = remainder
+		txs = append(txs, m)
+	}
+
+	for i, m := range txs {
+		m.CurrentIndex = int64(i)
+		m.LastIndex = int64(len(txs) - 1)
+		if err := m.Encode(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := finalize(txs); err != nil {
+		return nil, err
+	}
+
+	return txs, nil
+}
+
+// newTx creates a transaction with the tag used as tag and obsolete tag.
+func newTx(address hash.Hash, tag string, ts time.Time) (*node.Message, error) {
+	padded, err := trinary.PadTag(tag)
+	if err != nil {
+		return nil, errInvalidTag
+	}
+
+	m := &node.Message{
+		Address:     address,
+		Tag:         make([]int8, trinary.LenTritsFromTrytes(len(padded))),
+		ObsoleteTag: make([]int8, trinary.LenTritsFromTrytes(len(padded))),
+		Ts:          ts.Unix(),
+	}
+	if _, err := trinary.TritsFromTrytes(m.Tag, padded); err != nil {
+		return nil, err
+	}
+	copy(m.ObsoleteTag, m.Tag)
+
+	return m, nil
+}
+
+// messageFragments encodes s and splits it into signature message fragments, at least one.
+func messageFragments(s string) ([][]int8, error) {
+	trytes := trinary.StringToTrytes(s)
+
+	var fragments [][]int8
+
+	for len(fragments) == 0 || len(trytes) > 0 {
+		n := len(trytes)
+		if n > trinary.SignatureMessageFragmentTrytes {
+			n = trinary.SignatureMessageFragmentTrytes
+		}
+		fragment := make([]int8, signing.FragmentTrits)
+		if _, err := trinary.TritsFromTrytes(fragment, trytes[:n]); err != nil {
+			return nil, err
+		}
+		fragments = append(fragments, fragment)
+		trytes = trytes[n:]
+	}
+
+	return fragments, nil
+}
+
+// finalize computes the bundle hash and sets it on all transactions. As long as the normalized hash
+// contains the insecure value, the obsolete tag of the first transaction is incremented to change the hash.
+func finalize(txs Bundle) error {
+	for {
+		h := Hash(txs)
+
+		if !containsInsecureTryte(signing.NormalizeBundleHash(h.Trits())) {
+			for _, m := range txs {
+				m.Bundle = h
+				if err := m.Encode(); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		trinary.Increment(txs[0].ObsoleteTag)
+		if err := txs[0].Encode(); err != nil {
+			return err
+		}
+	}
+}
+
+func containsInsecureTryte(normalized []int8) bool {
+	for _, v := range normalized {
+		if v == insecureTryte {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,176 @@
+package bundle
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eaigner/igi/hash"
+	"github.com/eaigner/igi/signing"
+	"github.com/eaigner/igi/trinary"
+)
+
+const testSeed = "ZLNM9UHJWKTTDEZOTH9CXDEIFUJQCIACDPJIXPOWBDW9LTBHC9AQRIXTIHYLIIURLZCXNSTGNIVC9ISVB"
+
+func testAddress(t *testing.T, s string) hash.Hash {
+	h, err := hash.FromTrytes(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+// chain references the next transaction in the trunk of every transaction, like PoW would.
+func chain(t *testing.T, b Bundle) {
+	for i := len(b) - 2; i >= 0; i-- {
+		b[i].Trunk = b[i+1].TxHash()
+		if err := b[i].Encode(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestBuildMessage(t *testing.T) {
+	ts := time.Unix(1500000000, 0)
+	text := strings.Repeat("IOTA ", 500) // 5000 trytes, 3 transactions
+
+	builder := Builder{
+		Transfers: []Transfer{
+			{Address: testAddress(t, "CLAAFXEY9AHHCSZCXNKDRZEJHIAFVKYORWNOZAGFPAZYNTSLCXUAG9WBSXBRXYEDPVPLXYVDCBCEKRUBD"), Tag: "IGI", Message: text},
+			{Address: testAddress(t, "VAKOBMBWEMHGZPF9VXSK9SYBJQBSMPZBIVDGHQFDZWGK9UKLPMLREXGGTUTLAYKJJWTIXW9OFQBZGFAJB")},
+		},
+		Timestamp: ts,
+	}
+
+	b, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b) != 4 {
+		t.Fatal(len(b))
+	}
+
+	var trytes string
+	for _, m := range b[:3] {
+		if m.TagTrytes() != "IGI999999999999999999999999" || m.Address != builder.Transfers[0].Address {
+			t.Fatal(m.Debug())
+		}
+		trytes += m.SignatureMessageFragmentTrytes()
+	}
+	if s, err := trinary.TrytesToString(trinary.TrimPadding(trytes)); err != nil || s != text {
+		t.Fatal(s, err)
+	}
+	if b[3].Address != builder.Transfers[1].Address || b[3].SignatureMessageFragmentTrytes() != strings.Repeat("9", 2187) {
+		t.Fatal(b[3].Debug())
+	}
+
+	for _, m := range b {
+		if m.Ts != ts.Unix() || m.LastIndex != 3 || m.Bundle != b[0].Bundle {
+			t.Fatal(m.Debug())
+		}
+	}
+
+	chain(t, b)
+
+	if err := Validate(b); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuildValue(t *testing.T) {
+	s := signing.NewKerl()
+
+	seed := make([]int8, hash.SizeTrits)
+	trinary.TritsFromTrytes(seed, testSeed)
+
+	subseed, err := signing.Subseed(s, seed, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := signing.Key(s, subseed, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	input, err := hash.FromTrits(signing.Address(s, signing.Digests(s, append([]int8{}, key...))))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	builder := Builder{
+		Transfers: []Transfer{
+			{Address: testAddress(t, "VAKOBMBWEMHGZPF9VXSK9SYBJQBSMPZBIVDGHQFDZWGK9UKLPMLREXGGTUTLAYKJJWTIXW9OFQBZGFAJB"), Value: 60, Message: "Hello"},
+		},
+		Inputs:    []Input{{Address: input, Balance: 100, Security: 2}},
+		Remainder: testAddress(t, "DJOV9ECPUVCNZAYUOZNTEIFCAHBOYGWWKKNHYPRBYTNZEXJNACBHPFAGTTVJCWSST9UCPMHUUALEBWSUY"),
+	}
+
+	b, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := []int64{60, -100, 0, 40}
+	if len(b) != len(values) {
+		t.Fatal(len(b))
+	}
+	for i, m := range b {
+		if m.Value != values[i] {
+			t.Fatal(i, m.Value)
+		}
+	}
+	if s, err := b[0].MessageString(); err != nil || s != "Hello" {
+		t.Fatal(s, err)
+	}
+
+	normalized := signing.NormalizeBundleHash(b[0].Bundle.Trits())
+	for _, v := range normalized {
+		if v == insecureTryte {
+			t.Fatal(normalized)
+		}
+	}
+
+	// Sign the input
+	for i, m := range b[1:3] {
+		section := normalized[i*signing.FragmentSegments : (i+1)*signing.FragmentSegments]
+		m.SignatureMessageFragment = signing.SignatureFragment(s, section, key[i*signing.FragmentTrits:(i+1)*signing.FragmentTrits])
+		if err := m.Encode(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	chain(t, b)
+
+	if err := Validate(b); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuildInvalid(t *testing.T) {
+	address := testAddress(t, "CLAAFXEY9AHHCSZCXNKDRZEJHIAFVKYORWNOZAGFPAZYNTSLCXUAG9WBSXBRXYEDPVPLXYVDCBCEKRUBD")
+
+	type test struct {
+		builder Builder
+		err     error
+	}
+	table := []test{
+		{Builder{}, errNoTransfers},
+		{Builder{Transfers: []Transfer{{Address: address, Value: -1}}}, errNegativeValue},
+		{Builder{Transfers: []Transfer{{Address: address, Tag: strings.Repeat("A", 28)}}}, errInvalidTag},
+		{Builder{Transfers: []Transfer{{Address: address, Value: 1}}}, errInsufficientBalance},
+		{Builder{Transfers: []Transfer{{Address: address, Value: 1}}, Inputs: []Input{{Address: address, Balance: 1, Security: 4}}}, errInvalidSecurity},
+		{Builder{Transfers: []Transfer{{Address: address, Value: 1}}, Inputs: []Input{{Address: address, Balance: -1, Security: 1}}}, errInvalidInputBalance},
+		{Builder{Transfers: []Transfer{{Address: address, Value: 1}}, Inputs: []Input{{Address: address, Balance: 2, Security: 1}}}, errMissingRemainder},
+		{Builder{Transfers: []Transfer{{Address: address, Value: TotalSupply + 1}}}, errInvalidValue},
+	}
+
+	for i, v := range table {
+		if _, err := v.builder.Build(); err != v.err {
+			t.Fatal(i, err)
+		}
+	}
+
+	invalid := Builder{Transfers: []Transfer{{Address: address, Tag: "IGIi"}}}
+	if _, err := invalid.Build(); err == nil || err.Error() != `invalid tryte 'i' at position 3` {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,150 @@
+package bundle
+
+import (
+	"errors"
+
+	"github.com/eaigner/igi/hash"
+	"github.com/eaigner/igi/node"
+	"github.com/eaigner/igi/signing"
+)
+
+// TotalSupply is the total number of tokens in the network.
+const TotalSupply = 2779530283277761
+
+var (
+	errEmptyBundle      = errors.New("empty bundle")
+	errInvalidIndex     = errors.New("invalid transaction index")
+	errInvalidBundle    = errors.New("transaction belongs to another bundle")
+	errInvalidTrunk     = errors.New("trunk does not reference the next transaction in the bundle")
+	errInvalidValue     = errors.New("transaction value exceeds total supply")
+	errInvalidBalance   = errors.New("bundle values do not sum to zero")
+	errInvalidHash      = errors.New("invalid bundle hash")
+	errInvalidSignature = errors.New("invalid input signature")
+)
+
+// Bundle is a list of transactions sharing a bundle hash, ordered by CurrentIndex.
+type Bundle []*node.Message
+
+// Collect assembles a bundle for every tail transaction (CurrentIndex 0) among txs, by following trunk references
+// through txs up to LastIndex while the bundle hash matches the tail's. Incomplete bundles are omitted.
+// Reattachments of the same bundle result in multiple bundles sharing a bundle hash.
+func Collect(txs []*node.Message) []Bundle {
+	byHash := make(map[hash.Hash]*node.Message, len(txs))
+
+	for _, m := range txs {
+		byHash[m.TxHash()] = m
+	}
+
+	var bundles []Bundle
+
+	for _, tail := range txs {
+		if tail.CurrentIndex != 0 {
+			continue
+		}
+		if b := assemble(tail, byHash); b != nil {
+			bundles = append(bundles, b)
+		}
+	}
+
+	return bundles
+}
+
+func assemble(tail *node.Message, byHash map[hash.Hash]*node.Message) Bundle {
+	b := Bundle{tail}
+
+	for m := tail; m.CurrentIndex < m.LastIndex; {
+		// Guard against trunk cycles in malicious input
+		if int64(len(b)) > tail.LastIndex {
+			return nil
+		}
+		next, ok := byHash[m.Trunk]
+		if !ok || next.Bundle != tail.Bundle {
+			return nil
+		}
+		b = append(b, next)
+		m = next
+	}
+
+	return b
+}
+
+// Hash computes the bundle hash by absorbing the essence of every transaction with Kerl.
+func Hash(b Bundle) hash.Hash {
+	var kerl hash.Kerl
+
+	kerl.Reset(0)
+
+	for _, m := range b {
+		kerl.Absorb(m.Essence())
+	}
+
+	var h hash.Hash
+	kerl.Squeeze(h[:])
+
+	return h
+}
+
+// Validate checks the structure of a bundle (indices, trunk chaining, values and the bundle hash)
+// and the signatures of all inputs.
+func Validate(b Bundle) error {
+	if len(b) == 0 {
+		return errEmptyBundle
+	}
+
+	lastIndex := int64(len(b) - 1)
+	sum := int64(0)
+
+	for i, m := range b {
+		if m.CurrentIndex != int64(i) || m.LastIndex != lastIndex {
+			return errInvalidIndex
+		}
+		if m.Bundle != b[0].Bundle {
+			return errInvalidBundle
+		}
+		if i < len(b)-1 && m.Trunk != b[i+1].TxHash() {
+			return errInvalidTrunk
+		}
+		if m.Value > TotalSupply || m.Value < -TotalSupply {
+			return errInvalidValue
+		}
+		sum += m.Value
+		if sum > TotalSupply || sum < -TotalSupply {
+			return errInvalidValue
+		}
+	}
+
+	if sum != 0 {
+		return errInvalidBalance
+	}
+	if Hash(b) != b[0].Bundle {
+		return errInvalidHash
+	}
+
+	return validateSignatures(b)
+}
+
+// validateSignatures checks the signature of every input (transaction with a negative value).
+// Signatures of addresses with a security level above 1 continue in the following zero value
+// transactions of the same address.
+func validateSignatures(b Bundle) error {
+	for i, m := range b {
+		if m.Value >= 0 {
+			continue
+		}
+
+		fragments := [][]int8{m.SignatureMessageFragment}
+
+		for _, next := range b[i+1:] {
+			if next.Value != 0 || next.Address != m.Address {
+				break
+			}
+			fragments = append(fragments, next.SignatureMessageFragment)
+		}
+
+		if !signing.ValidateSignatures(signing.NewKerl(), m.Address.Trits(), fragments, m.Bundle.Trits()) {
+			return errInvalidSignature
+		}
+	}
+
+	return nil
+}
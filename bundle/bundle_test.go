@@ -0,0 +1,107 @@
+package bundle
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/eaigner/igi/node"
+)
+
+const bundleHash = "QFRKHPDUOHCDBKOKKGXOKYOAUZZIQLOCGWULEQJFUSJACVBZTZNCIVBB9FWICI9LUR9AEZKSHRPEOYLAZ"
+
+// readTxs reads transaction trytes, one per line.
+func readTxs(t *testing.T, path string) []*node.Message {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var txs []*node.Message
+
+	for _, line := range strings.Split(strings.TrimSpace(string(b)), "\n") {
+		m, err := node.ParseTxTrytes(line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		txs = append(txs, m)
+	}
+
+	return txs
+}
+
+func TestCollect(t *testing.T) {
+	txs := readTxs(t, "testdata/zero_value_bundle.txt")
+
+	// Reverse order and add an unrelated transaction
+	shuffled := []*node.Message{txs[2], txs[1], txs[0], txs[2]}
+
+	bundles := Collect(shuffled)
+
+	if len(bundles) != 1 {
+		t.Fatal(len(bundles))
+	}
+	for i, m := range bundles[0] {
+		if m != txs[i] {
+			t.Fatal(i)
+		}
+	}
+
+	// Incomplete bundles are omitted
+	if bundles := Collect(txs[:2]); len(bundles) != 0 {
+		t.Fatal(len(bundles))
+	}
+}
+
+func TestValidate(t *testing.T) {
+	b := Bundle(readTxs(t, "testdata/zero_value_bundle.txt"))
+
+	if err := Validate(b); err != nil {
+		t.Fatal(err)
+	}
+	if h := b[0].BundleTrytes(); h != bundleHash {
+		t.Fatal(h)
+	}
+	if err := Validate(b[:2]); err != errInvalidIndex {
+		t.Fatal(err)
+	}
+	if err := Validate(Bundle{b[0], b[2], b[1]}); err != errInvalidTrunk {
+		t.Fatal(err)
+	}
+
+	b[0].Value = 1
+	b[1].Value = -2
+
+	if err := Validate(b); err != errInvalidBalance {
+		t.Fatal(err)
+	}
+
+	b[0].Value = 0
+	b[1].Value = 0
+
+	// Flip an address trit, which is part of the essence
+	b[2].Essence()[0] = -b[2].Essence()[0]
+
+	if err := Validate(b); err != errInvalidHash {
+		t.Fatal(err)
+	}
+}
+
+func TestValidateSignatures(t *testing.T) {
+	b := Bundle(readTxs(t, "testdata/value_bundle.txt"))
+
+	if b[1].Value != -100 {
+		t.Fatal(b[1].Value)
+	}
+	if err := Validate(b); err != nil {
+		t.Fatal(err)
+	}
+
+	// Tamper with the second signature fragment of the input
+	f := b[2].SignatureMessageFragment
+	f[0] = (f[0]+2)%3 - 1
+
+	if err := Validate(b); err != errInvalidSignature {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,172 @@
+package hash
+
+import "errors"
+
+// BCTBatchSize is the number of inputs a BCTCurl hashes at once.
+const BCTBatchSize = 64
+
+var errInvalidBatch = errors.New("batch must hold 1 to 64 slices of equal length")
+
+// BCTCurl is a binary-coded ternary Curl that hashes up to BCTBatchSize inputs in parallel.
+// Every trit is stored as one bit in a low and one bit in a high word, bit k belonging to input k:
+// -1 is (1, 0), 0 is (1, 1) and 1 is (0, 1).
+type BCTCurl struct {
+	mode        int
+	low, high   [curlStateLength]uint64
+	scratchLow  [curlStateLength]uint64
+	scratchHigh [curlStateLength]uint64
+}
+
+func (c *BCTCurl) Reset(mode int) {
+	c.mode = mode
+	for i := range c.low {
+		c.low[i] = ^uint64(0)
+		c.high[i] = ^uint64(0)
+	}
+}
+
+// Absorb absorbs up to BCTBatchSize inputs of equal length.
+func (c *BCTCurl) Absorb(in [][]int8) error {
+	if !validBatch(in) {
+		return errInvalidBatch
+	}
+	c.absorb(in)
+	return nil
+}
+
+// Squeeze squeezes into up to BCTBatchSize outputs of equal length.
+func (c *BCTCurl) Squeeze(out [][]int8) error {
+	if !validBatch(out) {
+		return errInvalidBatch
+	}
+	c.squeeze(out)
+	return nil
+}
+
+func validBatch(b [][]int8) bool {
+	if len(b) == 0 || len(b) > BCTBatchSize {
+		return false
+	}
+	for _, v := range b {
+		if len(v) != len(b[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *BCTCurl) absorb(in [][]int8) {
+	for offset := 0; offset < len(in[0]); offset += SizeTrits {
+		n := len(in[0]) - offset
+		if n > SizeTrits {
+			n = SizeTrits
+		}
+		for i := 0; i < n; i++ {
+			low, high := ^uint64(0), ^uint64(0)
+			for k, v := range in {
+				switch v[offset+i] {
+				case 1:
+					low &^= 1 << uint(k)
+				case -1:
+					high &^= 1 << uint(k)
+				}
+			}
+			c.low[i] = low
+			c.high[i] = high
+		}
+		c.transform()
+	}
+}
+
+func (c *BCTCurl) squeeze(out [][]int8) {
+	for offset := 0; offset < len(out[0]); offset += SizeTrits {
+		n := len(out[0]) - offset
+		if n > SizeTrits {
+			n = SizeTrits
+		}
+		for i := 0; i < n; i++ {
+			low, high := c.low[i], c.high[i]
+			for k, v := range out {
+				l := low >> uint(k) & 1
+				h := high >> uint(k) & 1
+				v[offset+i] = int8(h) - int8(l)
+			}
+		}
+		c.transform()
+	}
+}
+
+// LoadCurl sets the state of all inputs to the state of a scalar Curl, e.g. a midstate shared by all inputs.
+func (c *BCTCurl) LoadCurl(curl *Curl) {
+	c.mode = curl.mode
+	for i, v := range curl.state {
+		c.low[i], c.high[i] = ^uint64(0), ^uint64(0)
+		switch v {
+		case 1:
+			c.low[i] = 0
+		case -1:
+			c.high[i] = 0
+		}
+	}
+}
+
+// AbsorbBCT absorbs trits that are already binary-coded. low and high must have equal length.
+func (c *BCTCurl) AbsorbBCT(low, high []uint64) {
+	for offset := 0; offset < len(low); offset += SizeTrits {
+		n := copy(c.low[:SizeTrits], low[offset:])
+		copy(c.high[:n], high[offset:])
+		c.transform()
+	}
+}
+
+// StateBCT returns the binary-coded trit i of the state, without squeezing.
+// For i < SizeTrits this is trit i of the hash the next squeeze would return.
+func (c *BCTCurl) StateBCT(i int) (low, high uint64) {
+	return c.low[i], c.high[i]
+}
+
+func (c *BCTCurl) transform() {
+	var i, j int
+	for round := 0; round < c.mode; round++ {
+		c.scratchLow = c.low // copy
+		c.scratchHigh = c.high
+		for k := 0; k < curlStateLength; k++ {
+			j = i
+			if i < 365 {
+				i += 364
+			} else {
+				i -= 365
+			}
+			alpha := c.scratchLow[j]
+			beta := c.scratchHigh[j]
+			gamma := c.scratchHigh[i]
+			delta := (alpha | ^gamma) & (c.scratchLow[i] ^ beta)
+			c.low[k] = ^delta
+			c.high[k] = (alpha ^ gamma) | delta
+		}
+	}
+}
+
+// HashBatch computes the Curl-P-81 hashes of all inputs, hashing runs of inputs with equal length in parallel.
+func HashBatch(in [][]int8) [][]int8 {
+	out := make([][]int8, len(in))
+	for i := range out {
+		out[i] = make([]int8, SizeTrits)
+	}
+
+	var curl BCTCurl
+
+	for start := 0; start < len(in); {
+		end := start + 1
+		for end < len(in) && end-start < BCTBatchSize && len(in[end]) == len(in[start]) {
+			end++
+		}
+		curl.Reset(CurlP81)
+		// Batches are built with 1 to BCTBatchSize inputs of equal length
+		curl.absorb(in[start:end])
+		curl.squeeze(out[start:end])
+		start = end
+	}
+
+	return out
+}
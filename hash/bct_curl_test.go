@@ -0,0 +1,118 @@
+package hash
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/eaigner/igi/trinary"
+)
+
+func TestHashBatch(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	// Mixed lengths and more than one batch
+	var in [][]int8
+	for i := 0; i < 2*BCTBatchSize+3; i++ {
+		n := 8019
+		if i%50 == 49 {
+			n = SizeTrits
+		}
+		v := make([]int8, n)
+		for j := range v {
+			v[j] = int8(r.Intn(3)) - 1
+		}
+		in = append(in, v)
+	}
+
+	out := HashBatch(in)
+
+	if len(out) != len(in) {
+		t.Fatal(len(out))
+	}
+
+	var curl Curl
+	var h [SizeTrits]int8
+
+	for i, v := range in {
+		curl.Reset(CurlP81)
+		curl.Absorb(v)
+		curl.Squeeze(h[:])
+
+		if !trinary.Equals(out[i], h[:]) {
+			t.Fatal(i)
+		}
+	}
+}
+
+func TestBCTCurlP81(t *testing.T) {
+	var in [8019]int8
+	var out [SizeTrits]int8
+
+	n, err := trinary.TritsFromTrytes(in[:], trytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var curl BCTCurl
+
+	curl.Reset(CurlP81)
+	if err := curl.Absorb([][]int8{in[:n]}); err != nil {
+		t.Fatal(err)
+	}
+	if err := curl.Squeeze([][]int8{out[:]}); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := trinary.Trytes(out[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != hash {
+		t.Fatal(s)
+	}
+}
+
+func TestBCTCurlInvalidBatch(t *testing.T) {
+	type test struct {
+		in [][]int8
+	}
+	table := []test{
+		{nil},
+		{[][]int8{}},
+		{[][]int8{make([]int8, SizeTrits), make([]int8, SizeTrits+1)}},
+		{make([][]int8, BCTBatchSize+1)},
+	}
+
+	var curl BCTCurl
+	curl.Reset(CurlP81)
+
+	for i, v := range table {
+		if err := curl.Absorb(v.in); err != errInvalidBatch {
+			t.Fatal(i, err)
+		}
+		if err := curl.Squeeze(v.in); err != errInvalidBatch {
+			t.Fatal(i, err)
+		}
+	}
+}
+
+func BenchmarkHashBatch(b *testing.B) {
+	var in [8019]int8
+
+	n, err := trinary.TritsFromTrytes(in[:], trytes)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	batch := make([][]int8, BCTBatchSize)
+	for i := range batch {
+		batch[i] = in[:n]
+	}
+
+	b.ResetTimer()
+
+	// Each iteration hashes BCTBatchSize transactions, compare with BenchmarkCurlP81 * BCTBatchSize.
+	for i := 0; i < b.N; i++ {
+		HashBatch(batch)
+	}
+}
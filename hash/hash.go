@@ -1,6 +1,11 @@
 package hash
 
-import "bytes"
+import (
+	"bytes"
+	"errors"
+
+	"github.com/eaigner/igi/trinary"
+)
 
 const (
 	SizeBytes = 49 // a hash would only require 46 bytes, but curl needs more to compute
@@ -9,8 +14,74 @@ const (
 
 var (
 	nullBytes = make([]byte, SizeBytes)
+
+	errInvalidHashLength = errors.New("invalid hash length")
 )
 
+// Hash is a hash of SizeTrits trits. Unlike []int8 it is comparable, so it can be used as a map key.
+type Hash [SizeTrits]int8
+
+// FromTrits copies SizeTrits trits into a Hash.
+func FromTrits(t []int8) (Hash, error) {
+	var h Hash
+	if len(t) != SizeTrits {
+		return h, errInvalidHashLength
+	}
+	copy(h[:], t)
+	return h, nil
+}
+
+// FromTrytes converts 81 trytes into a Hash.
+func FromTrytes(s string) (Hash, error) {
+	var h Hash
+	if trinary.LenTritsFromTrytes(len(s)) != SizeTrits {
+		return h, errInvalidHashLength
+	}
+	_, err := trinary.TritsFromTrytes(h[:], s)
+	return h, err
+}
+
+// FromBytes converts a compact SizeBytes hash, see Pack, into a Hash.
+func FromBytes(b []byte) (Hash, error) {
+	var h Hash
+	t := Unpack(b)
+	if t == nil {
+		return h, errInvalidHashLength
+	}
+	copy(h[:], t)
+	return h, nil
+}
+
+// Trits returns the hash trits.
+func (h Hash) Trits() []int8 {
+	return h[:]
+}
+
+// Trytes returns the hash as 81 trytes.
+func (h Hash) Trytes() string {
+	s, _ := trinary.Trytes(h[:]) // a hash always has a multiple of 3 trits
+	return s
+}
+
+func (h Hash) String() string {
+	return h.Trytes()
+}
+
+// Bytes returns the compact SizeBytes representation of the hash, which is used as storage key.
+func (h Hash) Bytes() []byte {
+	return Pack(h[:])
+}
+
+// Zero returns true if h is the zero hash.
+func (h Hash) Zero() bool {
+	return h == Hash{}
+}
+
+// WeightMagnitude returns the number of trailing zero trits.
+func (h Hash) WeightMagnitude() int {
+	return WeightMagnitude(h[:])
+}
+
 // WeightMagnitude returns the weight magnitude of trits.
 func WeightMagnitude(trits []int8) int {
 	last := len(trits) - 1
@@ -50,16 +121,15 @@ func ValidInt8(hash []int8) bool {
 	return len(hash) == SizeTrits && !ZeroInt8(hash)
 }
 
-// ToBytes converts an int8 hash to bytes
+// ToBytes converts an int8 hash to bytes, using one byte per trit (trinary.T1B1).
 func ToBytes(hash []int8) []byte {
-	buf := make([]byte, len(hash))
-	for i, v := range hash {
-		buf[i] = byte(v)
-	}
+	buf := make([]byte, trinary.T1B1.EncodedLen(len(hash)))
+	trinary.T1B1.Encode(buf, hash) // buffer is always large enough
 	return buf
 }
 
-// ToInt8 converts a byte hash to int8
+// ToInt8 converts a byte hash with one byte per trit (trinary.T1B1) to int8.
+// Bytes are converted as is, use trinary.T1B1 to reject bytes that are not valid trits.
 func ToInt8(hash []byte) []int8 {
 	buf := make([]int8, len(hash))
 	for i, v := range hash {
@@ -67,3 +137,21 @@ func ToInt8(hash []byte) []int8 {
 	}
 	return buf
 }
+
+// Pack converts an int8 hash to its compact SizeBytes representation, using 5 trits per byte.
+func Pack(hash []int8) []byte {
+	buf := make([]byte, trinary.LenBytes(len(hash)))
+	trinary.Bytes(buf, hash) // buffer is always large enough
+	return buf
+}
+
+// Unpack converts a compact SizeBytes hash back to SizeTrits int8 trits.
+// Returns nil if b is not SizeBytes long.
+func Unpack(b []byte) []int8 {
+	if len(b) != SizeBytes {
+		return nil
+	}
+	buf := make([]int8, trinary.LenTrits(len(b)))
+	trinary.Trits(buf, b) // buffer is always large enough
+	return buf[:SizeTrits]
+}
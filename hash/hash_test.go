@@ -58,3 +58,83 @@ func TestZeroInt8(t *testing.T) {
 		t.Fatal()
 	}
 }
+
+func TestPack(t *testing.T) {
+	h := make([]int8, SizeTrits)
+	for i := range h {
+		h[i] = int8(i%3) - 1
+	}
+
+	b := Pack(h)
+
+	if len(b) != SizeBytes {
+		t.Fatal(len(b))
+	}
+	if !Valid(b) {
+		t.Fatal(b)
+	}
+
+	u := Unpack(b)
+
+	if len(u) != SizeTrits {
+		t.Fatal(len(u))
+	}
+	for i, v := range u {
+		if v != h[i] {
+			t.Fatal(i, v)
+		}
+	}
+	if Unpack(b[1:]) != nil {
+		t.Fatal()
+	}
+}
+
+func TestHash(t *testing.T) {
+	const trytes = "QFRKHPDUOHCDBKOKKGXOKYOAUZZIQLOCGWULEQJFUSJACVBZTZNCIVBB9FWICI9LUR9AEZKSHRPEOYLAZ"
+
+	h, err := FromTrytes(trytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Trytes() != trytes || h.String() != trytes {
+		t.Fatal(h.Trytes())
+	}
+	if h.Zero() || !(Hash{}).Zero() {
+		t.Fatal()
+	}
+
+	h2, err := FromTrits(h.Trits())
+	if err != nil {
+		t.Fatal(err)
+	}
+	h3, err := FromBytes(h.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h2 != h || h3 != h {
+		t.Fatal(h2, h3)
+	}
+
+	// Usable as map key
+	m := map[Hash]bool{h: true}
+	if !m[h3] {
+		t.Fatal()
+	}
+
+	h[SizeTrits-1] = 0
+	h[SizeTrits-2] = 0
+	h[SizeTrits-3] = 1
+	if w := h.WeightMagnitude(); w != 2 {
+		t.Fatal(w)
+	}
+
+	if _, err := FromTrits(h.Trits()[1:]); err != errInvalidHashLength {
+		t.Fatal(err)
+	}
+	if _, err := FromTrytes(trytes[1:]); err != errInvalidHashLength {
+		t.Fatal(err)
+	}
+	if _, err := FromBytes(h.Bytes()[1:]); err != errInvalidHashLength {
+		t.Fatal(err)
+	}
+}
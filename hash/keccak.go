@@ -0,0 +1,109 @@
+package hash
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+const (
+	keccak384Size = 48
+	keccak384Rate = 200 - 2*keccak384Size
+)
+
+var keccakRoundConstants = [24]uint64{
+	0x0000000000000001, 0x0000000000008082, 0x800000000000808a, 0x8000000080008000,
+	0x000000000000808b, 0x0000000080000001, 0x8000000080008081, 0x8000000000008009,
+	0x000000000000008a, 0x0000000000000088, 0x0000000080008009, 0x000000008000000a,
+	0x000000008000808b, 0x800000000000008b, 0x8000000000008089, 0x8000000000008003,
+	0x8000000000008002, 0x8000000000000080, 0x000000000000800a, 0x800000008000000a,
+	0x8000000080008081, 0x8000000000008080, 0x0000000080000001, 0x8000000080008008,
+}
+
+// keccakRotations holds the rotation offset of lane x+5*y.
+var keccakRotations = [25]int{
+	0, 1, 62, 28, 27,
+	36, 44, 6, 55, 20,
+	3, 10, 43, 25, 39,
+	41, 45, 15, 21, 8,
+	18, 2, 61, 56, 14,
+}
+
+// keccak384 implements the original Keccak-384 hash (with 0x01 padding, as opposed to SHA3-384).
+type keccak384 struct {
+	state [25]uint64
+	buf   [keccak384Rate]byte
+	n     int // bytes buffered in buf
+}
+
+func (k *keccak384) reset() {
+	k.state = [25]uint64{}
+	k.n = 0
+}
+
+func (k *keccak384) write(p []byte) {
+	for len(p) > 0 {
+		c := copy(k.buf[k.n:], p)
+		k.n += c
+		p = p[c:]
+		if k.n == keccak384Rate {
+			k.absorbBlock()
+		}
+	}
+}
+
+// sum pads the buffered input and writes the digest to out. The state must be reset before it is used again.
+func (k *keccak384) sum(out *[keccak384Size]byte) {
+	for i := k.n; i < keccak384Rate; i++ {
+		k.buf[i] = 0
+	}
+	k.buf[k.n] ^= 0x01
+	k.buf[keccak384Rate-1] ^= 0x80
+	k.absorbBlock()
+
+	for i := 0; i < keccak384Size/8; i++ {
+		binary.LittleEndian.PutUint64(out[i*8:], k.state[i])
+	}
+}
+
+func (k *keccak384) absorbBlock() {
+	for i := 0; i < keccak384Rate/8; i++ {
+		k.state[i] ^= binary.LittleEndian.Uint64(k.buf[i*8:])
+	}
+	keccakF1600(&k.state)
+	k.n = 0
+}
+
+func keccakF1600(a *[25]uint64) {
+	var b [25]uint64
+	var c [5]uint64
+
+	for round := 0; round < 24; round++ {
+		// θ
+		for x := 0; x < 5; x++ {
+			c[x] = a[x] ^ a[x+5] ^ a[x+10] ^ a[x+15] ^ a[x+20]
+		}
+		for x := 0; x < 5; x++ {
+			d := c[(x+4)%5] ^ bits.RotateLeft64(c[(x+1)%5], 1)
+			for y := 0; y < 25; y += 5 {
+				a[y+x] ^= d
+			}
+		}
+
+		// ρ and π
+		for x := 0; x < 5; x++ {
+			for y := 0; y < 5; y++ {
+				b[y+5*((2*x+3*y)%5)] = bits.RotateLeft64(a[x+5*y], keccakRotations[x+5*y])
+			}
+		}
+
+		// χ
+		for y := 0; y < 25; y += 5 {
+			for x := 0; x < 5; x++ {
+				a[y+x] = b[y+x] ^ (^b[y+(x+1)%5] & b[y+(x+2)%5])
+			}
+		}
+
+		// ι
+		a[0] ^= keccakRoundConstants[round]
+	}
+}
@@ -0,0 +1,53 @@
+package hash
+
+import "github.com/eaigner/igi/trinary"
+
+// Kerl is the Keccak-384 based ternary sponge used for bundle hashes, addresses and signatures.
+// Trits are absorbed and squeezed in chunks of SizeTrits. Every chunk is converted to a 384-bit
+// big-endian two's complement integer, with the last trit treated as zero.
+type Kerl struct {
+	keccak    keccak384
+	squeezing bool
+	buf       [keccak384Size]byte
+}
+
+// Reset resets the sponge state. mode is ignored, Kerl has no round modes.
+func (k *Kerl) Reset(mode int) {
+	k.keccak.reset()
+	k.squeezing = false
+}
+
+// Absorb absorbs trits. len(v) must be a multiple of SizeTrits and Absorb must not be called after Squeeze
+// without a Reset.
+func (k *Kerl) Absorb(v []int8) {
+	if len(v)%SizeTrits != 0 {
+		panic("kerl: absorb length must be a multiple of 243 trits")
+	}
+	if k.squeezing {
+		panic("kerl: absorb after squeeze")
+	}
+	for ; len(v) > 0; v = v[SizeTrits:] {
+		trinary.T243B48.Encode(k.buf[:], v[:SizeTrits]) // sizes always match
+		k.keccak.write(k.buf[:])
+	}
+}
+
+// Squeeze squeezes trits into v. len(v) must be a multiple of SizeTrits.
+func (k *Kerl) Squeeze(v []int8) {
+	if len(v)%SizeTrits != 0 {
+		panic("kerl: squeeze length must be a multiple of 243 trits")
+	}
+	for ; len(v) > 0; v = v[SizeTrits:] {
+		// Every further chunk hashes the bitwise complement of the previous digest.
+		if k.squeezing {
+			for i := range k.buf {
+				k.buf[i] = ^k.buf[i]
+			}
+			k.keccak.reset()
+			k.keccak.write(k.buf[:])
+		}
+		k.squeezing = true
+		k.keccak.sum(&k.buf)
+		trinary.T243B48.Decode(v[:SizeTrits], k.buf[:]) // sizes always match
+	}
+}
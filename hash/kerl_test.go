@@ -0,0 +1,81 @@
+package hash
+
+import (
+	"testing"
+
+	"github.com/eaigner/igi/trinary"
+)
+
+func TestKerl(t *testing.T) {
+	type test struct {
+		in, out string
+	}
+	table := []test{
+		{
+			"HHPELNTNJIOKLYDUW9NDULWPHCWFRPTDIUWLYUHQWWJVPAKKGKOAZFJPQJBLNDPALCVXGJLRBFSHATF9C",
+			"DMJWZTDJTASXZTHZFXFZXWMNFHRTKWFUPCQJXEBJCLRZOM9LPVJSTCLFLTQTDGMLVUHOVJHBBUYFD9AXX",
+		},
+		{
+			"QAUGQZQKRAW9GKEFIBUD9BMJQOABXBTFELCT9GVSZCPTZOSFBSHPQRWJLLWURPXKNAOWCSVWUBNDSWMPW",
+			"HOVOHFEPCIGTOFEAZVXAHQRFFRTPQEEKANKFKIHUKSGRICVADWDMBINDYKRCCIWBEOPXXIKMLNSOHEAQZ",
+		},
+		{
+			// output with non-zero 243rd trit
+			"GYOMKVTSNHVJNCNFBBAH9AAMXLPLLLROQY99QN9DLSJUHDPBLCFFAIQXZA9BKMBJCYSFHFPXAHDWZFEIZ",
+			"OXJCNFHUNAHWDLKKPELTBFUCVW9KLXKOGWERKTJXQMXTKFKNWNNXYD9DMJJABSEIONOSJTTEVKVDQEWTW",
+		},
+		{
+			// input with non-zero 243rd trit
+			"EMIDYNHBWMBCXVDEFOFWINXTERALUKYYPPHKP9JJFGJEIUY9MUDVNFZHMMWZUYUSWAIOWEVTHNWMHANBH",
+			"EJEAOOZYSAWFPZQESYDHZCGYNSTWXUMVJOVDWUNZJXDGWCLUFGIMZRMGCAZGKNPLBRLGUNYWKLJTYEAQX",
+		},
+		{
+			// output with more than 243 trits
+			"9MIDYNHBWMBCXVDEFOFWINXTERALUKYYPPHKP9JJFGJEIUY9MUDVNFZHMMWZUYUSWAIOWEVTHNWMHANBH",
+			"G9JYBOMPUXHYHKSNRNMMSSZCSHOFYOYNZRSZMAAYWDYEIMVVOGKPJBVBM9TDPULSFUNMTVXRKFIDOHUXXVYDLFSZYZTWQYTE9SPYYWYTXJYQ9IFGYOLZXWZBKWZN9QOOTBQMWMUBLEWUEEASRHRTNIQWJQNDWRYLCA",
+		},
+		{
+			// input and output with more than 243 trits
+			"G9JYBOMPUXHYHKSNRNMMSSZCSHOFYOYNZRSZMAAYWDYEIMVVOGKPJBVBM9TDPULSFUNMTVXRKFIDOHUXXVYDLFSZYZTWQYTE9SPYYWYTXJYQ9IFGYOLZXWZBKWZN9QOOTBQMWMUBLEWUEEASRHRTNIQWJQNDWRYLCA",
+			"LUCKQVACOGBFYSPPVSSOXJEKNSQQRQKPZC9NXFSMQNRQCGGUL9OHVVKBDSKEQEBKXRNUJSRXYVHJTXBPDWQGNSCDCBAIRHAQCOWZEBSNHIJIGPZQITIBJQ9LNTDIBTCQ9EUWKHFLGFUVGGUWJONK9GBCDUIMAYMMQX",
+		},
+	}
+
+	var kerl Kerl
+	var sponge Sponge = &kerl // to test interface conformance
+
+	for _, v := range table {
+		in := make([]int8, trinary.LenTritsFromTrytes(len(v.in)))
+		out := make([]int8, trinary.LenTritsFromTrytes(len(v.out)))
+
+		if _, err := trinary.TritsFromTrytes(in, v.in); err != nil {
+			t.Fatal(err)
+		}
+
+		sponge.Reset(0)
+		sponge.Absorb(in)
+		sponge.Squeeze(out)
+
+		s, err := trinary.Trytes(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != v.out {
+			t.Logf("is:   %s", s)
+			t.Logf("want: %s", v.out)
+			t.FailNow()
+		}
+	}
+}
+
+func BenchmarkKerl(b *testing.B) {
+	var in [SizeTrits]int8
+	var out [SizeTrits]int8
+	var kerl Kerl
+
+	for i := 0; i < b.N; i++ {
+		kerl.Reset(0)
+		kerl.Absorb(in[:])
+		kerl.Squeeze(out[:])
+	}
+}
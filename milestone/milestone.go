@@ -0,0 +1,145 @@
+package milestone
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/eaigner/igi/bundle"
+	"github.com/eaigner/igi/hash"
+	"github.com/eaigner/igi/node"
+	"github.com/eaigner/igi/signing"
+	"github.com/eaigner/igi/storage"
+	"github.com/eaigner/igi/trinary"
+)
+
+const (
+	indexTrits = 15 // the milestone index is encoded in the first trits of the tail's obsolete tag
+	maxDepth   = signing.FragmentSegments
+)
+
+var (
+	errNotMilestone      = errors.New("bundle is not sent to the coordinator")
+	errInvalidStructure  = errors.New("invalid milestone bundle structure")
+	errInvalidIndex      = errors.New("milestone index exceeds the coordinator's keys")
+	errInvalidSignature  = errors.New("invalid milestone signature")
+	errInvalidDepth      = errors.New("invalid coordinator merkle tree depth")
+	errInvalidMilestone  = errors.New("invalid stored milestone")
+	errMilestoneNotFound = errors.New("milestone not found")
+)
+
+// Coordinator describes the coordinator that issues milestones.
+type Coordinator struct {
+	Address  hash.Hash      // merkle root of the coordinator's keys
+	Depth    int            // merkle tree depth, the coordinator has 2^Depth keys
+	Security int            // security level of the coordinator's keys
+	Sponge   signing.Sponge // Curl-P-27 for legacy coordinators, Kerl otherwise
+}
+
+// Milestone is a valid milestone.
+type Milestone struct {
+	Index uint32
+	Hash  hash.Hash // hash of the tail transaction
+}
+
+// IsMilestone returns true if m was sent to the coordinator address.
+func (c *Coordinator) IsMilestone(m *node.Message) bool {
+	return m.Address == c.Address
+}
+
+// Validate checks that b is a milestone bundle issued by the coordinator.
+// A milestone bundle consists of Security transactions carrying the signature fragments, followed by
+// a head transaction carrying the merkle path of the signing key. The signature signs the hash of the
+// head transaction and the index of the signing key in the merkle tree is the milestone index.
+func (c *Coordinator) Validate(b bundle.Bundle) (*Milestone, error) {
+	if c.Depth < 0 || c.Depth > maxDepth {
+		return nil, errInvalidDepth
+	}
+	if len(b) != c.Security+1 {
+		return nil, errInvalidStructure
+	}
+	if !c.IsMilestone(b[0]) {
+		return nil, errNotMilestone
+	}
+	if err := bundle.Validate(b); err != nil {
+		return nil, err
+	}
+
+	head := b[c.Security]
+
+	for _, m := range b[:c.Security] {
+		if m.Branch != head.Trunk {
+			return nil, errInvalidStructure
+		}
+	}
+
+	index := trinary.Int64(b[0].ObsoleteTag[:indexTrits])
+	if index < 0 || index >= 1<<uint(c.Depth) {
+		return nil, errInvalidIndex
+	}
+
+	normalized := signing.NormalizeBundleHash(head.TxHash().Trits())
+	digests := make([]int8, c.Security*hash.SizeTrits)
+
+	for i, m := range b[:c.Security] {
+		section := normalized[i*signing.FragmentSegments : (i+1)*signing.FragmentSegments]
+		copy(digests[i*hash.SizeTrits:], signing.Digest(c.Sponge, section, m.SignatureMessageFragment))
+	}
+
+	leaf := signing.Address(c.Sponge, digests)
+	root := signing.MerkleRoot(c.Sponge, leaf, head.SignatureMessageFragment, uint64(index), c.Depth)
+
+	if !trinary.Equals(root, c.Address.Trits()) {
+		return nil, errInvalidSignature
+	}
+
+	return &Milestone{Index: uint32(index), Hash: b[0].TxHash()}, nil
+}
+
+// Store records a valid milestone in the milestone bucket, keyed by index.
+func Store(store storage.Store, ms *Milestone) error {
+	return storage.Write(store, indexKey(ms.Index), ms.Hash.Bytes(), storage.MilestoneBucket)
+}
+
+// Load reads the milestone with the given index.
+func Load(store storage.Store, index uint32) (*Milestone, error) {
+	v, err := storage.Read(store, indexKey(index), storage.MilestoneBucket)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, errMilestoneNotFound
+	}
+	h, err := hash.FromBytes(v)
+	if err != nil {
+		return nil, errInvalidMilestone
+	}
+	return &Milestone{Index: index, Hash: h}, nil
+}
+
+// Latest returns the milestone with the highest index, or nil if no milestone was recorded yet.
+func Latest(store storage.Store) (*Milestone, error) {
+	var latest *Milestone
+
+	err := store.ForEach(storage.MilestoneBucket, func(k, v []byte) error {
+		h, err := hash.FromBytes(v)
+		if len(k) != 4 || err != nil {
+			return errInvalidMilestone
+		}
+		if index := binary.BigEndian.Uint32(k); latest == nil || index > latest.Index {
+			latest = &Milestone{Index: index, Hash: h}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return latest, nil
+}
+
+// indexKey encodes index big-endian, so milestones are ordered by index in the bucket.
+func indexKey(index uint32) []byte {
+	var k [4]byte
+	binary.BigEndian.PutUint32(k[:], index)
+	return k[:]
+}
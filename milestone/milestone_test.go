@@ -0,0 +1,291 @@
+package milestone
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/eaigner/igi/bundle"
+	"github.com/eaigner/igi/hash"
+	"github.com/eaigner/igi/node"
+	"github.com/eaigner/igi/signing"
+	"github.com/eaigner/igi/storage"
+	"github.com/eaigner/igi/trinary"
+)
+
+const testDepth = 2
+
+// testCoordinator is a coordinator with 2^testDepth keys.
+type testCoordinator struct {
+	Coordinator
+	keys     [][]int8
+	siblings [][]int8 // merkle path of every key
+}
+
+func newTestCoordinator(t *testing.T, s signing.Sponge, security int) *testCoordinator {
+	seed := make([]int8, hash.SizeTrits)
+	seed[0] = 1
+
+	var level [][]int8
+
+	tc := &testCoordinator{}
+
+	for i := 0; i < 1<<testDepth; i++ {
+		subseed, err := signing.Subseed(s, seed, int64(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		key, err := signing.Key(s, subseed, security)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tc.keys = append(tc.keys, key)
+		tc.siblings = append(tc.siblings, nil)
+		level = append(level, signing.Address(s, signing.Digests(s, append([]int8{}, key...))))
+	}
+
+	for d := 0; d < testDepth; d++ {
+		for i := range tc.siblings {
+			tc.siblings[i] = append(tc.siblings[i], level[(i>>uint(d))^1]...)
+		}
+		var next [][]int8
+		for i := 0; i < len(level); i += 2 {
+			h := make([]int8, hash.SizeTrits)
+			s.Reset(s.Mode)
+			s.Absorb(append(append([]int8{}, level[i]...), level[i+1]...))
+			s.Squeeze(h)
+			next = append(next, h)
+		}
+		level = next
+	}
+
+	address, err := hash.FromTrits(level[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tc.Coordinator = Coordinator{Address: address, Depth: testDepth, Security: security, Sponge: s}
+
+	return tc
+}
+
+// milestone creates a milestone bundle for index, signed with the key at keyIndex.
+func (tc *testCoordinator) milestone(t *testing.T, index int64, keyIndex int) bundle.Bundle {
+	b := make(bundle.Bundle, tc.Security+1)
+
+	for i := range b {
+		b[i] = &node.Message{
+			Address:      tc.Address,
+			ObsoleteTag:  make([]int8, indexTrits),
+			Ts:           1500000000,
+			CurrentIndex: int64(i),
+			LastIndex:    int64(tc.Security),
+		}
+		if err := trinary.FromInt64(b[i].ObsoleteTag, index); err != nil {
+			t.Fatal(err)
+		}
+		encode(t, b[i])
+	}
+
+	bundleHash := bundle.Hash(b)
+
+	head := b[tc.Security]
+	head.SignatureMessageFragment = tc.siblings[keyIndex]
+	head.Trunk[0] = 1
+	head.Branch[0] = -1
+
+	for _, m := range b {
+		m.Bundle = bundleHash
+		encode(t, m)
+	}
+
+	normalized := signing.NormalizeBundleHash(head.TxHash().Trits())
+	next := head
+
+	for i := tc.Security - 1; i >= 0; i-- {
+		section := normalized[i*signing.FragmentSegments : (i+1)*signing.FragmentSegments]
+		keyFragment := tc.keys[keyIndex][i*signing.FragmentTrits : (i+1)*signing.FragmentTrits]
+		b[i].SignatureMessageFragment = signing.SignatureFragment(tc.Sponge, section, keyFragment)
+		b[i].Trunk = next.TxHash()
+		b[i].Branch = head.Trunk
+		encode(t, b[i])
+		next = b[i]
+	}
+
+	return b
+}
+
+func encode(t *testing.T, m *node.Message) {
+	if err := m.Encode(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	type test struct {
+		sponge   signing.Sponge
+		security int
+	}
+	table := []test{
+		{signing.NewCurlP27(), 1},
+		{signing.NewKerl(), 2},
+	}
+
+	for _, v := range table {
+		tc := newTestCoordinator(t, v.sponge, v.security)
+
+		for index := int64(0); index < 1<<testDepth; index++ {
+			ms, err := tc.Validate(tc.milestone(t, index, int(index)))
+			if err != nil {
+				t.Fatal(v.sponge.Mode, index, err)
+			}
+			if int64(ms.Index) != index {
+				t.Fatal(ms.Index)
+			}
+		}
+
+		// Signed with the key of another index
+		if _, err := tc.Validate(tc.milestone(t, 2, 1)); err != errInvalidSignature {
+			t.Fatal(err)
+		}
+
+		// Index exceeds the merkle tree
+		if _, err := tc.Validate(tc.milestone(t, 1<<testDepth, 0)); err != errInvalidIndex {
+			t.Fatal(err)
+		}
+
+		// Missing head transaction
+		b := tc.milestone(t, 0, 0)
+		if _, err := tc.Validate(b[:len(b)-1]); err != errInvalidStructure {
+			t.Fatal(err)
+		}
+
+		// Other coordinator
+		other := tc.Coordinator
+		other.Address = hash.Hash{}
+		if _, err := other.Validate(b); err != errNotMilestone {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestTracker(t *testing.T) {
+	dbPath := "test_milestone.db"
+
+	os.Remove(dbPath)
+
+	store, err := storage.NewBoltStore(dbPath, storage.Mainnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	defer os.Remove(dbPath)
+
+	tc := newTestCoordinator(t, signing.NewCurlP27(), 2)
+	tracker := NewTracker(&tc.Coordinator, store, node.NewNullLogger())
+
+	// The tail arrives first, the bundle is validated once the head is stored
+	b := tc.milestone(t, 3, 3)
+
+	for _, m := range []*node.Message{b[0], b[2], b[1]} {
+		if err := m.Store(store); err != nil {
+			t.Fatal(err)
+		}
+		if ms, err := Latest(store); err != nil || ms != nil {
+			t.Fatal(ms, err)
+		}
+		tracker.Process(m)
+	}
+
+	ms, err := Latest(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ms.Index != 3 || ms.Hash != b[0].TxHash() {
+		t.Fatal(ms.Index)
+	}
+
+	// An invalid milestone is not recorded
+	invalid := tc.milestone(t, 2, 1)
+	for _, m := range invalid {
+		if err := m.Store(store); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	n, err := NewTracker(&tc.Coordinator, store, node.NewNullLogger()).Scan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatal(n)
+	}
+	if _, err := Load(store, 2); err != errMilestoneNotFound {
+		t.Fatal(err)
+	}
+	if ms, err := Load(store, 3); err != nil || ms.Index != 3 {
+		t.Fatal(ms, err)
+	}
+}
+
+// fakeTail creates a milestone tail of an incomplete bundle that is never stored.
+func fakeTail(t *testing.T, coo *Coordinator, i int64) *node.Message {
+	m := &node.Message{Address: coo.Address, LastIndex: 1}
+	if err := trinary.FromInt64(m.Bundle[:], i+1); err != nil {
+		t.Fatal(err)
+	}
+	encode(t, m)
+	return m
+}
+
+func TestTrackerPendingLimit(t *testing.T) {
+	dbPath := "test_milestone_pending.db"
+
+	os.Remove(dbPath)
+
+	store, err := storage.NewBoltStore(dbPath, storage.Mainnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	defer os.Remove(dbPath)
+
+	tc := newTestCoordinator(t, signing.NewCurlP27(), 1)
+	tracker := NewTracker(&tc.Coordinator, store, node.NewNullLogger())
+
+	now := time.Unix(1500000000, 0)
+	tracker.now = func() time.Time { return now }
+	tracker.limit = 16
+
+	for i := int64(0); i < int64(tracker.limit); i++ {
+		tracker.Process(fakeTail(t, &tc.Coordinator, i))
+		now = now.Add(time.Millisecond)
+	}
+	if len(tracker.pending) != tracker.limit {
+		t.Fatal(len(tracker.pending))
+	}
+
+	// A valid milestone is still recorded, evicting the oldest fake tail
+	b := tc.milestone(t, 1, 1)
+	for _, m := range b {
+		if err := m.Store(store); err != nil {
+			t.Fatal(err)
+		}
+		tracker.Process(m)
+	}
+
+	if ms, err := Latest(store); err != nil || ms == nil || ms.Index != 1 {
+		t.Fatal(ms, err)
+	}
+	if len(tracker.pending) != tracker.limit-1 {
+		t.Fatal(len(tracker.pending))
+	}
+
+	// Expired tails are removed when the next tail arrives
+	now = now.Add(pendingTTL + time.Second)
+	tracker.Process(fakeTail(t, &tc.Coordinator, int64(tracker.limit)))
+
+	if len(tracker.pending) != 1 {
+		t.Fatal(len(tracker.pending))
+	}
+}
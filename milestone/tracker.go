@@ -0,0 +1,172 @@
+package milestone
+
+import (
+	"sync"
+	"time"
+
+	"github.com/eaigner/igi/bundle"
+	"github.com/eaigner/igi/hash"
+	"github.com/eaigner/igi/node"
+	"github.com/eaigner/igi/storage"
+)
+
+// Anyone can send transactions to the coordinator address, so incomplete milestone bundles are only
+// kept for pendingTTL and the oldest one is evicted when more than maxPending are incomplete.
+const (
+	maxPending = 1024
+	pendingTTL = 10 * time.Minute
+)
+
+// Tracker detects milestones among stored transactions and records the valid ones.
+type Tracker struct {
+	coo     *Coordinator
+	store   storage.Store
+	logger  node.Logger
+	mu      sync.Mutex
+	pending map[hash.Hash]pendingTail // tails of incomplete milestone bundles
+	now     func() time.Time
+	limit   int // maximum number of pending tails
+}
+
+type pendingTail struct {
+	tail  *node.Message
+	added time.Time
+}
+
+func NewTracker(coo *Coordinator, store storage.Store, logger node.Logger) *Tracker {
+	return &Tracker{
+		coo:     coo,
+		store:   store,
+		logger:  logger,
+		pending: make(map[hash.Hash]pendingTail),
+		now:     time.Now,
+		limit:   maxPending,
+	}
+}
+
+// Process is called for every stored transaction. Milestone bundles are validated as soon as
+// all of their transactions are stored.
+func (t *Tracker) Process(m *node.Message) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if m.CurrentIndex == 0 && t.coo.IsMilestone(m) {
+		t.evict()
+		t.pending[m.TxHash()] = pendingTail{m, t.now()}
+	}
+
+	for key, p := range t.pending {
+		tail := p.tail
+		if tail.Bundle != m.Bundle {
+			continue
+		}
+		b, err := loadBundle(t.store, tail)
+		if err != nil {
+			t.logger.Printf("error loading milestone bundle: %v", err)
+			continue
+		}
+		if b == nil {
+			continue // incomplete
+		}
+		delete(t.pending, key)
+		t.record(b)
+	}
+}
+
+// evict removes expired tails and makes room for a new one by removing the oldest.
+func (t *Tracker) evict() {
+	now := t.now()
+
+	for key, p := range t.pending {
+		if now.Sub(p.added) > pendingTTL {
+			delete(t.pending, key)
+		}
+	}
+
+	for len(t.pending) >= t.limit {
+		var oldest hash.Hash
+		var added time.Time
+
+		for key, p := range t.pending {
+			if added.IsZero() || p.added.Before(added) {
+				oldest, added = key, p.added
+			}
+		}
+
+		t.logger.Println("too many incomplete milestones, evicting the oldest milestone tail")
+		delete(t.pending, oldest)
+	}
+}
+
+// Scan validates and records the milestones of all stored transactions, e.g. after an import.
+// Returns the number of valid milestones.
+func (t *Tracker) Scan() (int, error) {
+	var tails []*node.Message
+
+	err := t.store.ForEach(storage.TransactionBucket, func(k, v []byte) error {
+		// v is only valid inside fn, but the tails are kept
+		m, err := node.ParseTxBytes(append([]byte(nil), v...))
+		if err != nil {
+			return err
+		}
+		if m.CurrentIndex == 0 && t.coo.IsMilestone(m) {
+			tails = append(tails, m)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+
+	for _, tail := range tails {
+		b, err := loadBundle(t.store, tail)
+		if err != nil {
+			return n, err
+		}
+		if b != nil && t.record(b) {
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+// record validates and stores a milestone bundle. Returns true if it is a valid milestone.
+func (t *Tracker) record(b bundle.Bundle) bool {
+	ms, err := t.coo.Validate(b)
+	if err != nil {
+		t.logger.Printf("invalid milestone: %v", err)
+		return false
+	}
+	if err := Store(t.store, ms); err != nil {
+		t.logger.Printf("milestone not stored: %v", err)
+		return false
+	}
+	t.logger.Printf("milestone %d stored", ms.Index)
+	return true
+}
+
+// loadBundle follows the trunks of tail through the stored transactions.
+// Returns nil if a transaction of the bundle is missing.
+func loadBundle(store storage.Store, tail *node.Message) (bundle.Bundle, error) {
+	b := bundle.Bundle{tail}
+
+	for m := tail; m.CurrentIndex < m.LastIndex && int64(len(b)) <= tail.LastIndex; {
+		v, err := storage.Read(store, m.Trunk.Bytes(), storage.TransactionBucket)
+		if err != nil {
+			return nil, err
+		}
+		if v == nil {
+			return nil, nil
+		}
+		if m, err = node.ParseTxBytes(v); err != nil {
+			return nil, err
+		}
+		b = append(b, m)
+	}
+
+	return b, nil
+}
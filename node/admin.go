@@ -0,0 +1,94 @@
+package node
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+
+	"github.com/eaigner/igi/storage"
+)
+
+// Admin serves administrative HTTP endpoints.
+// It exposes the whole database, so it should only listen on a trusted interface.
+type Admin struct {
+	host   string
+	logger Logger
+	store  storage.Store
+	server *http.Server
+}
+
+func NewAdmin(host string, logger Logger, store storage.Store) *Admin {
+	return &Admin{
+		host:   host,
+		logger: logger,
+		store:  store,
+	}
+}
+
+// Listen starts serving the admin endpoints. An empty host disables the admin server.
+func (admin *Admin) Listen() error {
+	if admin.host == "" {
+		admin.logger.Printf("admin disabled")
+		return nil
+	}
+
+	ln, err := net.Listen("tcp", admin.host)
+	if err != nil {
+		return err
+	}
+
+	admin.server = &http.Server{Handler: admin.handler()}
+	admin.logger.Printf("admin listening on http://%v", ln.Addr())
+
+	go func() {
+		if err := admin.server.Serve(ln); err != http.ErrServerClosed {
+			admin.logger.Printf("admin server error: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+func (admin *Admin) Close() error {
+	if admin.server == nil {
+		return nil
+	}
+	return admin.server.Close()
+}
+
+func (admin *Admin) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/backup", admin.handleBackup)
+	mux.HandleFunc("/stats", admin.handleStats)
+	return mux
+}
+
+// handleBackup streams a consistent snapshot of the running database.
+func (admin *Admin) handleBackup(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", `attachment; filename="tangle.db"`)
+
+	n, err := admin.store.Backup(w)
+	if err != nil {
+		// Headers are already sent, the client will see a truncated body.
+		admin.logger.Printf("error writing backup: %v", err)
+		return
+	}
+
+	admin.logger.Printf("backup written (%d bytes)", n)
+}
+
+// handleStats responds with database and tangle statistics as JSON.
+func (admin *Admin) handleStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := ReadStats(admin.store)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(stats); err != nil {
+		admin.logger.Printf("error writing stats: %v", err)
+	}
+}
@@ -0,0 +1,144 @@
+package node
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/eaigner/igi/storage"
+)
+
+func TestAdminBackup(t *testing.T) {
+	dbPath := "test_admin.db"
+	backupPath := "test_admin_backup.db"
+
+	os.Remove(dbPath)
+	os.Remove(backupPath)
+
+	store, err := storage.NewBoltStore(dbPath, storage.Mainnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	defer os.Remove(dbPath)
+
+	msg, err := ParseUdpBytes(msgBytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := msg.Store(store); err != nil {
+		t.Fatal(err)
+	}
+
+	admin := NewAdmin("", NewNullLogger(), store)
+	server := httptest.NewServer(admin.handler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatal(resp.Status)
+	}
+
+	f, err := os.Create(backupPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(backupPath)
+
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	backup, err := storage.NewBoltStore(backupPath, storage.Mainnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer backup.Close()
+
+	if ok, err := storage.Exists(backup, msg.TxHash().Bytes(), storage.TransactionBucket); err != nil || !ok {
+		t.Fatal(ok, err)
+	}
+}
+
+func TestAdminDisabled(t *testing.T) {
+	admin := NewAdmin("", NewNullLogger(), nil)
+
+	// Closing before listening is a no-op
+	if err := admin.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := admin.Listen(); err != nil {
+		t.Fatal(err)
+	}
+	if admin.server != nil {
+		t.Fatal("admin server started without host")
+	}
+	if err := admin.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAdminStats(t *testing.T) {
+	dbPath := "test_admin_stats.db"
+
+	os.Remove(dbPath)
+
+	store, err := storage.NewBoltStore(dbPath, storage.Mainnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	defer os.Remove(dbPath)
+
+	msg, err := ParseUdpBytes(msgBytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := msg.Store(store); err != nil {
+		t.Fatal(err)
+	}
+
+	// Keys that are not packed hashes, e.g. malformed legacy keys, are not counted as transactions
+	malformed := make([]byte, 243)
+	malformed[0] = 5
+	if err := storage.Write(store, malformed, msg.TxBytes, storage.TransactionBucket); err != nil {
+		t.Fatal(err)
+	}
+
+	admin := NewAdmin("", NewNullLogger(), store)
+	server := httptest.NewServer(admin.handler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/stats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatal(resp.Status)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Fatal(ct)
+	}
+
+	var stats Stats
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		t.Fatal(err)
+	}
+	if stats.Storage.Network != storage.Mainnet || stats.Storage.Buckets[storage.TransactionBucket.String()].Keys != 2 {
+		t.Fatal(stats.Storage)
+	}
+	if stats.Tangle != (TangleStats{Transactions: 1, Tips: 1, Solid: 0, Confirmed: 0}) {
+		t.Fatal(stats.Tangle)
+	}
+}
@@ -6,6 +6,7 @@ type Conf struct {
 	HttpHost           string
 	UdpHost            string
 	TcpHost            string
+	AdminHost          string
 	DbPath             string
 	Debug              bool
 	Testnet            bool
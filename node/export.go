@@ -0,0 +1,73 @@
+package node
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/eaigner/igi/storage"
+	"github.com/eaigner/igi/trinary"
+)
+
+// Export writes every stored transaction to w, one line of trytes per transaction.
+// Returns the number of exported transactions.
+func Export(w io.Writer, store storage.Store) (int, error) {
+	bw := bufio.NewWriter(w)
+	n := 0
+
+	var buf [trinary.TxTrits]int8
+
+	err := store.ForEach(storage.TransactionBucket, func(k, v []byte) error {
+		m, err := ParseTxBytesInto(&buf, v)
+		if err != nil {
+			return err
+		}
+		if _, err := bw.WriteString(m.TxTrytes() + "\n"); err != nil {
+			return err
+		}
+		n++
+		return nil
+	})
+
+	if err != nil {
+		return n, err
+	}
+
+	return n, bw.Flush()
+}
+
+// Import reads transactions written by Export (or IRI) from r, validates and stores them.
+// Transactions that already exist are skipped.
+// Returns the number of imported and skipped transactions.
+func Import(r io.Reader, store storage.Store, minWeightMag int) (imported int, skipped int, err error) {
+	sc := bufio.NewScanner(r)
+	line := 0
+
+	for sc.Scan() {
+		line++
+
+		s := sc.Text()
+		if len(s) == 0 {
+			continue
+		}
+
+		m, err := ParseTxTrytes(s)
+		if err != nil {
+			return imported, skipped, fmt.Errorf("line %d: %v", line, err)
+		}
+		if err := m.Validate(minWeightMag); err != nil {
+			return imported, skipped, fmt.Errorf("line %d: %v", line, err)
+		}
+
+		switch err := m.Store(store); err {
+		case nil:
+			imported++
+		case errTxAlreadyExists:
+			skipped++
+		default:
+			return imported, skipped, err
+		}
+	}
+
+	return imported, skipped, sc.Err()
+}
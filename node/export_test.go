@@ -0,0 +1,71 @@
+package node
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/eaigner/igi/storage"
+)
+
+func TestExportImport(t *testing.T) {
+	dbPath := "test_export.db"
+
+	os.Remove(dbPath)
+
+	store, err := storage.NewBoltStore(dbPath, storage.Mainnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	defer os.Remove(dbPath)
+
+	msg, err := ParseUdpBytes(msgBytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	in := msg.TxTrytes() + "\n"
+
+	if len(in) != txnTrytes+1 {
+		t.Fatal(len(in))
+	}
+
+	imported, skipped, err := Import(strings.NewReader(in), store, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported != 1 || skipped != 0 {
+		t.Fatal(imported, skipped)
+	}
+
+	// Importing twice skips existing transactions
+	imported, skipped, err = Import(strings.NewReader(in), store, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported != 0 || skipped != 1 {
+		t.Fatal(imported, skipped)
+	}
+
+	var out bytes.Buffer
+
+	n, err := Export(&out, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatal(n)
+	}
+	if out.String() != in {
+		t.Fatal(out.String())
+	}
+
+	if _, _, err := Import(strings.NewReader("ABC\n"), store, 0); err == nil {
+		t.Fatal("should fail on malformed line")
+	}
+	if _, _, err := Import(strings.NewReader(strings.ToLower(in)), store, 0); err == nil || err.Error() != "line 1: "+errInvalidTxChars.Error() {
+		t.Fatal(err)
+	}
+}
@@ -1,19 +1,19 @@
 package node
 
 import (
-	"crypto/sha256"
 	"encoding/hex"
 	"errors"
 	"fmt"
-	"github.com/eaigner/igi/storage"
+	"unicode/utf8"
 
 	"github.com/eaigner/igi/hash"
+	"github.com/eaigner/igi/storage"
 	"github.com/eaigner/igi/trinary"
 )
 
 const (
 	signatureMessageFragmentTrinaryOffset      = 0
-	signatureMessageFragmentTrinarySize        = 6561
+	signatureMessageFragmentTrinarySize        = trinary.SignatureMessageFragmentTrits
 	addressTrinaryOffset                       = signatureMessageFragmentTrinaryOffset + signatureMessageFragmentTrinarySize
 	addressTrinarySize                         = 243
 	valueTrinaryOffset                         = addressTrinaryOffset + addressTrinarySize
@@ -42,7 +42,7 @@ const (
 	attachmentTimestampUpperBoundTrinaryOffset = attachmentTimestampLowerBoundTrinaryOffset + attachmentTimestampLowerBoundTrinarySize
 	attachmentTimestampUpperBoundTrinarySize   = 27
 	nonceTrinaryOffset                         = attachmentTimestampUpperBoundTrinaryOffset + attachmentTimestampUpperBoundTrinarySize
-	nonceTrinarySize                           = 81
+	nonceTrinarySize                           = trinary.NonceTrits
 	trinarySize                                = nonceTrinaryOffset + nonceTrinarySize
 	essenceTrinaryOffset                       = addressTrinaryOffset
 	essenceTrinarySize                         = addressTrinarySize + valueTrinarySize + obsoleteTagTrinarySize + timestampTrinarySize + currentIndexTrinarySize + lastIndexTrinarySize
@@ -52,42 +52,48 @@ const (
 	hashSizeTrits       = 243
 	udpPacketBytes      = 1650
 	hashTrailerBytes    = 46
+	txnTrytes           = trinarySize / 3
 	txnPacketBytes      = udpPacketBytes - hashTrailerBytes
 	hashesInvalidBefore = 1508760000
 )
 
 var (
 	errMessageTooShort    = errors.New("message too short")
+	errInvalidTxTrytes    = errors.New("invalid transaction trytes length")
+	errInvalidTxChars     = errors.New("transaction contains invalid tryte characters")
 	errTxAlreadyExists    = errors.New("transaction already exists")
 	errInvalidTxTimestamp = errors.New("invalid transaction timestamp")
 	errInvalidTxValue     = errors.New("invalid transaction value")
 	errInvalidTxHash      = errors.New("invalid transaction hash")
 	errInvalidTxAddress   = errors.New("invalid transaction address")
+	errTxFieldTooLong     = errors.New("message field does not fit into the transaction")
+	errInvalidTxMessage   = errors.New("signature message fragment is not a text message")
 )
 
 type Message struct {
-	TxBytes           []byte // Raw transaction bytes
-	TxTrits           []int8 // Raw transaction trits
-	Address           []int8 // Address trits
-	Trunk             []int8 // Trunk address trits
-	Branch            []int8 // Branch address trits
-	Bundle            []int8 // Bundle address trits
-	Tag               []int8 // Tag
-	ObsoleteTag       []int8
-	Nonce             []int8 // Nonce
-	ValueTrailer      []int8 // Trits after usable value
-	AttachmentTs      int64  // Attachment timestamp
-	AttachmentTsUpper int64  // Attachment timestamp upper bound
-	AttachmentTsLower int64  // Attachment timestamp lower bound
-	Value             int64  // Transaction value
-	Ts                int64
-	CurrentIndex      int64
-	LastIndex         int64
-	Trailer           []byte // UDP packet trailer. Only set if message was read with ParseUdpBytes.
-
-	txHash      []int8
-	trailerHash []int8
-	digest      []byte // SHA-256 digest of the transaction packet, excluding trailer bytes
+	TxBytes                  []byte    // Raw transaction bytes
+	TxTrits                  []int8    // Raw transaction trits
+	SignatureMessageFragment []int8    // Signature of an input, or message data
+	Address                  hash.Hash // Address trits
+	Trunk                    hash.Hash // Trunk transaction hash
+	Branch                   hash.Hash // Branch transaction hash
+	Bundle                   hash.Hash // Bundle hash
+	Tag                      []int8    // Tag
+	ObsoleteTag              []int8
+	Nonce                    []int8 // Nonce
+	ValueTrailer             []int8 // Trits after usable value
+	AttachmentTs             int64  // Attachment timestamp
+	AttachmentTsUpper        int64  // Attachment timestamp upper bound
+	AttachmentTsLower        int64  // Attachment timestamp lower bound
+	Value                    int64  // Transaction value
+	Ts                       int64
+	CurrentIndex             int64
+	LastIndex                int64
+	Trailer                  []byte // UDP packet trailer. Only set if message was read with ParseUdpBytes.
+
+	txHash      hash.Hash
+	hashed      bool // txHash was computed
+	trailerHash hash.Hash
 }
 
 // ParseUdpBytes parses a transaction including UDP trailer bytes.
@@ -100,28 +106,43 @@ func ParseUdpBytes(b []byte) (*Message, error) {
 		return nil, err
 	}
 	m.Trailer = b[txnPacketBytes:]
+	if _, err := trinary.Trits(m.trailerHash[:], m.Trailer); err != nil {
+		return nil, err
+	}
 	return m, nil
 }
 
 // ParseTxBytes parses transaction bytes without an UDP trailer.
 func ParseTxBytes(b []byte) (*Message, error) {
+	return ParseTxBytesInto(new([trinary.TxTrits]int8), b)
+}
+
+// ParseTxBytesInto parses transaction bytes like ParseTxBytes, but decodes the trits into buf.
+// The message references buf, so buf must not be reused while the message is in use.
+func ParseTxBytesInto(buf *[trinary.TxTrits]int8, b []byte) (*Message, error) {
 	if len(b) != txnPacketBytes {
 		return nil, errMessageTooShort
 	}
 
-	t := make([]int8, trinary.LenTrits(len(b)))
-	_, err := trinary.Trits(t, b)
-	if err != nil {
-		return nil, err
-	}
+	var packed [trinary.TxBytes]byte
+	copy(packed[:], b)
+	trinary.TxTritsFromBytes(buf, &packed)
 
 	m := new(Message)
 	m.TxBytes = b
+	m.setTrits(buf[:])
+
+	return m, nil
+}
+
+// setTrits sets the transaction trits and decodes the fields from them.
+func (m *Message) setTrits(t []int8) {
 	m.TxTrits = t
-	m.Address = chunk(t, addressTrinaryOffset, addressTrinarySize)
-	m.Trunk = chunk(t, trunkTransactionTrinaryOffset, trunkTransactionTrinarySize)
-	m.Branch = chunk(t, branchTransactionTrinaryOffset, branchTransactionTrinarySize)
-	m.Bundle = chunk(t, bundleTrinaryOffset, bundleTrinarySize)
+	m.SignatureMessageFragment = chunk(t, signatureMessageFragmentTrinaryOffset, signatureMessageFragmentTrinarySize)
+	m.Address = chunkHash(t, addressTrinaryOffset)
+	m.Trunk = chunkHash(t, trunkTransactionTrinaryOffset)
+	m.Branch = chunkHash(t, branchTransactionTrinaryOffset)
+	m.Bundle = chunkHash(t, bundleTrinaryOffset)
 	m.Tag = chunk(t, tagTrinaryOffset, tagTrinarySize)
 	m.ObsoleteTag = chunk(t, obsoleteTagTrinaryOffset, obsoleteTagTrinarySize)
 	m.Nonce = chunk(t, nonceTrinaryOffset, nonceTrinarySize)
@@ -133,48 +154,137 @@ func ParseTxBytes(b []byte) (*Message, error) {
 	m.Ts = chunkInt64(t, timestampTrinaryOffset, timestampTrinarySize)
 	m.CurrentIndex = chunkInt64(t, currentIndexTrinaryOffset, currentIndexTrinarySize)
 	m.LastIndex = chunkInt64(t, lastIndexTrinaryOffset, lastIndexTrinarySize)
-
-	return m, nil
 }
 
-func (m *Message) TxDigest() []byte {
-	if len(m.digest) < sha256.Size {
-		d := sha256.Sum256(m.TxBytes)
-		m.digest = d[:]
+// ParseTxTrytes parses a transaction from its tryte representation.
+func ParseTxTrytes(s string) (*Message, error) {
+	if len(s) != txnTrytes {
+		return nil, errInvalidTxTrytes
+	}
+	if !trinary.ValidTrytes(s) {
+		return nil, errInvalidTxChars
+	}
+
+	t := make([]int8, trinary.LenTrits(txnPacketBytes))
+	if _, err := trinary.TritsFromTrytes(t, s); err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, txnPacketBytes)
+	if _, err := trinary.Bytes(b, t); err != nil {
+		return nil, err
 	}
-	return m.digest
-}
 
-func (m Message) TxDigestHex() string {
-	return hex.EncodeToString(m.TxDigest())
+	return ParseTxBytes(b)
 }
 
-func (m *Message) TxHash() []int8 {
-	if len(m.txHash) > 0 {
+func (m *Message) TxHash() hash.Hash {
+	if m.hashed {
 		return m.txHash
 	}
 
-	m.txHash = make([]int8, hash.SizeTrits)
-
 	var curl hash.Curl
 	curl.Reset(hash.CurlP81)
 	curl.Absorb(m.TxTrits[:trinarySize])
-	curl.Squeeze(m.txHash)
+	curl.Squeeze(m.txHash[:])
+
+	m.hashed = true
 
 	return m.txHash
 }
 
-func (m *Message) TrailerHash() []int8 {
-	if len(m.trailerHash) > 0 {
-		return m.trailerHash
+// MarshalTrits builds the transaction trits from the message fields.
+// Trit fields shorter than their place in the transaction are padded with zeros.
+func (m *Message) MarshalTrits() ([]int8, error) {
+	t := make([]int8, trinary.TxTrits)
+
+	fields := []struct {
+		src    []int8
+		offset int
+		size   int
+	}{
+		{m.SignatureMessageFragment, signatureMessageFragmentTrinaryOffset, signatureMessageFragmentTrinarySize},
+		{m.Address[:], addressTrinaryOffset, addressTrinarySize},
+		{m.ValueTrailer, valueTrinaryOffset + valueUsableTrinarySize, valueTrinarySize - valueUsableTrinarySize},
+		{m.ObsoleteTag, obsoleteTagTrinaryOffset, obsoleteTagTrinarySize},
+		{m.Bundle[:], bundleTrinaryOffset, bundleTrinarySize},
+		{m.Trunk[:], trunkTransactionTrinaryOffset, trunkTransactionTrinarySize},
+		{m.Branch[:], branchTransactionTrinaryOffset, branchTransactionTrinarySize},
+		{m.Tag, tagTrinaryOffset, tagTrinarySize},
+		{m.Nonce, nonceTrinaryOffset, nonceTrinarySize},
+	}
+	for _, f := range fields {
+		if len(f.src) > f.size {
+			return nil, errTxFieldTooLong
+		}
+		copy(t[f.offset:f.offset+f.size], f.src)
+	}
+
+	if err := trinary.FromInt64(chunk(t, valueTrinaryOffset, valueUsableTrinarySize), m.Value); err != nil {
+		return nil, errInvalidTxValue
+	}
+
+	ints := []struct {
+		v      int64
+		offset int
+		size   int
+	}{
+		{m.Ts, timestampTrinaryOffset, timestampTrinarySize},
+		{m.CurrentIndex, currentIndexTrinaryOffset, currentIndexTrinarySize},
+		{m.LastIndex, lastIndexTrinaryOffset, lastIndexTrinarySize},
+		{m.AttachmentTs, attachmentTimestampTrinaryOffset, attachmentTimestampTrinarySize},
+		{m.AttachmentTsLower, attachmentTimestampLowerBoundTrinaryOffset, attachmentTimestampLowerBoundTrinarySize},
+		{m.AttachmentTsUpper, attachmentTimestampUpperBoundTrinaryOffset, attachmentTimestampUpperBoundTrinarySize},
+	}
+	for _, f := range ints {
+		if err := trinary.FromInt64(chunk(t, f.offset, f.size), f.v); err != nil {
+			return nil, errTxFieldTooLong
+		}
 	}
 
-	m.trailerHash = make([]int8, hash.SizeTrits)
+	return t, nil
+}
 
-	_, err := trinary.Trits(m.trailerHash, m.Trailer)
+// Encode rebuilds TxTrits and TxBytes from the message fields, e.g. after the fields were modified.
+// The trit fields reference the new TxTrits afterwards and the transaction hash is recomputed on the next call to TxHash.
+func (m *Message) Encode() error {
+	t, err := m.MarshalTrits()
 	if err != nil {
-		return nil
+		return err
+	}
+
+	var trits [trinary.TxTrits]int8
+	var b [trinary.TxBytes]byte
+	copy(trits[:], t)
+	trinary.TxBytesFromTrits(&b, &trits)
+
+	m.TxBytes = b[:]
+	m.setTrits(trits[:])
+	m.hashed = false
+
+	return nil
+}
+
+// HashMessages computes the transaction hashes of all messages, hashing them in parallel batches.
+func HashMessages(msgs []*Message) {
+	if len(msgs) == 1 {
+		msgs[0].TxHash()
+		return
+	}
+
+	in := make([][]int8, len(msgs))
+	for i, m := range msgs {
+		in[i] = m.TxTrits[:trinarySize]
+	}
+	for i, h := range hash.HashBatch(in) {
+		copy(msgs[i].txHash[:], h)
+		msgs[i].hashed = true
 	}
+}
+
+// TrailerHash returns the hash of the transaction requested by the sender in the UDP trailer.
+// Only set if message was read with ParseUdpBytes.
+func (m *Message) TrailerHash() hash.Hash {
 	return m.trailerHash
 }
 
@@ -182,13 +292,13 @@ func (m Message) Validate(minWeightMag int) error {
 	txHash := m.TxHash()
 
 	// Check weight magnitude
-	if hash.WeightMagnitude(txHash[:]) < minWeightMag {
+	if txHash.WeightMagnitude() < minWeightMag {
 		return errInvalidTxHash
 	}
 
 	// Every non-zero hash before 'Mon 23rd Oct 2017 12:00:00 PM' is invalid.
 	// Taken from IRI.
-	if m.Ts < hashesInvalidBefore && hash.ZeroInt8(txHash[:]) {
+	if m.Ts < hashesInvalidBefore && txHash.Zero() {
 		return errInvalidTxTimestamp
 	}
 
@@ -200,7 +310,7 @@ func (m Message) Validate(minWeightMag int) error {
 	}
 
 	// Check if last address trit is zero.
-	if m.Value != 0 && len(m.Address) == hash.SizeTrits && m.Address[hash.SizeTrits-1] != 0 {
+	if m.Value != 0 && m.Address[hash.SizeTrits-1] != 0 {
 		return errInvalidTxAddress
 	}
 
@@ -210,11 +320,11 @@ func (m Message) Validate(minWeightMag int) error {
 // Store stores the message in the tangle.
 // Returns an error if storage failed or the transaction already exists.
 func (m Message) Store(tangle storage.Store) error {
-	if !hash.ValidInt8(m.TxHash()) {
+	if m.TxHash().Zero() {
 		return errInvalidTxHash
 	}
 
-	txHash := hash.ToBytes(m.TxHash())
+	txHash := m.TxHash().Bytes()
 
 	// TODO(era): make exists and write check atomic
 	exists, err := storage.Exists(tangle, txHash, storage.TransactionBucket)
@@ -229,20 +339,48 @@ func (m Message) Store(tangle storage.Store) error {
 	return storage.Write(tangle, txHash, m.TxBytes, storage.TransactionBucket)
 }
 
+// Essence returns the trits that make up the bundle hash: address, value, obsolete tag, timestamp and indices.
+func (m Message) Essence() []int8 {
+	return chunk(m.TxTrits, essenceTrinaryOffset, essenceTrinarySize)
+}
+
+// TxTrytes returns the transaction as trytes, in the format used by IRI.
+func (m Message) TxTrytes() string {
+	return toTryte(m.TxTrits[:trinarySize])
+}
+
+// SignatureMessageFragmentTrytes returns the signature or message data as trytes.
+func (m Message) SignatureMessageFragmentTrytes() string {
+	return toTryte(m.SignatureMessageFragment)
+}
+
+// MessageString decodes the signature message fragment as text encoded with trinary.StringToTrytes,
+// ignoring trailing padding. Returns an error if the fragment does not contain a message.
+func (m Message) MessageString() (string, error) {
+	s, err := trinary.TrytesToString(trinary.TrimPadding(m.SignatureMessageFragmentTrytes()))
+	if err != nil {
+		return "", err
+	}
+	if !utf8.ValidString(s) {
+		return "", errInvalidTxMessage
+	}
+	return s, nil
+}
+
 func (m Message) AddressTrytes() string {
-	return toTryte(m.Address)
+	return m.Address.Trytes()
 }
 
 func (m Message) BundleTrytes() string {
-	return toTryte(m.Bundle)
+	return m.Bundle.Trytes()
 }
 
 func (m Message) TrunkTrytes() string {
-	return toTryte(m.Trunk)
+	return m.Trunk.Trytes()
 }
 
 func (m Message) BranchTrytes() string {
-	return toTryte(m.Branch)
+	return m.Branch.Trytes()
 }
 
 func (m Message) ObsoleteTagTrytes() string {
@@ -263,11 +401,11 @@ func toTryte(t []int8) string {
 }
 
 func (m *Message) Debug() string {
-	return fmt.Sprintf("<Message address=%s trunk=%s branch=%s bundle=%s tag=%s otag=%s nonce=%s ats=%v atsh=%v atsl=%v value=%v ts=%v index=%v lindex=%v trailer=%s digest=%s>",
-		trytes(m.Address),
-		trytes(m.Trunk),
-		trytes(m.Branch),
-		trytes(m.Bundle),
+	return fmt.Sprintf("<Message address=%s trunk=%s branch=%s bundle=%s tag=%s otag=%s nonce=%s ats=%v atsh=%v atsl=%v value=%v ts=%v index=%v lindex=%v trailer=%s hash=%s>",
+		m.Address,
+		m.Trunk,
+		m.Branch,
+		m.Bundle,
 		trytes(m.Tag),
 		trytes(m.ObsoleteTag),
 		trytes(m.Nonce),
@@ -279,7 +417,7 @@ func (m *Message) Debug() string {
 		m.CurrentIndex,
 		m.LastIndex,
 		hex.EncodeToString(m.Trailer),
-		hex.EncodeToString(m.TxDigest()),
+		m.TxHash(),
 	)
 }
 
@@ -292,6 +430,12 @@ func chunk(t []int8, offset, size int) []int8 {
 	return t[offset : offset+size]
 }
 
+func chunkHash(t []int8, offset int) hash.Hash {
+	var h hash.Hash
+	copy(h[:], t[offset:offset+hash.SizeTrits])
+	return h
+}
+
 func chunkInt64(t []int8, offset, size int) int64 {
 	return trinary.Int64(t[offset : offset+size])
 }
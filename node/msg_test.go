@@ -1,9 +1,12 @@
 package node
 
 import (
+	"bytes"
 	"encoding/hex"
 	"strings"
 	"testing"
+
+	"github.com/eaigner/igi/trinary"
 )
 
 const msgHex = `00000000000000000000000000000000000000000000000000000000000000
@@ -111,3 +114,229 @@ func TestParseUdpBytes(t *testing.T) {
 		t.Fatal(v)
 	}
 }
+
+func TestHashMessages(t *testing.T) {
+	var msgs, expect []*Message
+
+	for i := 0; i < 3; i++ {
+		m1, err := ParseUdpBytes(msgBytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		m2, err := ParseUdpBytes(msgBytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgs = append(msgs, m1)
+		expect = append(expect, m2)
+	}
+
+	HashMessages(msgs)
+
+	for i, m := range msgs {
+		if m.txHash != expect[i].TxHash() {
+			t.Fatal(i)
+		}
+	}
+}
+
+func TestParseTxTrytesInvalid(t *testing.T) {
+	msg, err := ParseUdpBytes(msgBytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := msg.TxTrytes()
+
+	if _, err := ParseTxTrytes(s); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseTxTrytes(s[:100] + "x" + s[101:]); err != errInvalidTxChars {
+		t.Fatal(err)
+	}
+	if _, err := ParseTxTrytes(s[1:]); err != errInvalidTxTrytes {
+		t.Fatal(err)
+	}
+}
+
+func TestParseTxBytesInto(t *testing.T) {
+	b := msgBytes()[:txnPacketBytes]
+
+	expect, err := ParseTxBytes(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf [trinary.TxTrits]int8
+	msg, err := ParseTxBytesInto(&buf, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !trinary.Equals(msg.TxTrits, expect.TxTrits) || msg.TxHash() != expect.TxHash() {
+		t.Fatal(msg.TxTrytes())
+	}
+	if &msg.TxTrits[0] != &buf[0] {
+		t.Fatal("trits not decoded into buffer")
+	}
+	if _, err := ParseTxBytesInto(&buf, b[1:]); err != errMessageTooShort {
+		t.Fatal(err)
+	}
+}
+
+func BenchmarkParseTxBytes(b *testing.B) {
+	tx := msgBytes()[:txnPacketBytes]
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		ParseTxBytes(tx)
+	}
+}
+
+func BenchmarkParseTxBytesInto(b *testing.B) {
+	tx := msgBytes()[:txnPacketBytes]
+	var buf [trinary.TxTrits]int8
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		ParseTxBytesInto(&buf, tx)
+	}
+}
+
+func TestMarshalTrits(t *testing.T) {
+	msg, err := ParseTxBytes(msgBytes()[:txnPacketBytes])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	trits, err := msg.MarshalTrits()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !trinary.Equals(trits, msg.TxTrits) {
+		t.Fatal(msg.TxTrytes())
+	}
+
+	b := msg.TxBytes
+	if err := msg.Encode(); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(msg.TxBytes, b) {
+		t.Fatal(msg.TxBytes)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	msg, err := ParseTxBytes(msgBytes()[:txnPacketBytes])
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := msg.TxHash()
+
+	msg.Value = -1234567
+	msg.Tag = []int8{1, 0, -1}
+	msg.Trunk = msg.Branch
+	msg.SignatureMessageFragment = []int8{1, 1, 1}
+	msg.AttachmentTsUpper = 1234
+
+	if err := msg.Encode(); err != nil {
+		t.Fatal(err)
+	}
+	if msg.TxHash() == h {
+		t.Fatal("hash not recomputed")
+	}
+
+	m, err := ParseTxBytes(msg.TxBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Value != -1234567 || m.AttachmentTsUpper != 1234 || m.Ts != 1515328739 || m.LastIndex != 1 {
+		t.Fatal(m.Debug())
+	}
+	if m.TagTrytes() != "S99999999999999999999999999" || m.TrunkTrytes() != msg.BranchTrytes() {
+		t.Fatal(m.Debug())
+	}
+	if m.AddressTrytes() != `XNZBYAST9BETSDNOVQKKTBECYIPMF9IPOZRWUPFQGVH9HJW9NDSQVIPVBWU9YKECRYGDSJXYMZGHZDXCA` {
+		t.Fatal(m.AddressTrytes())
+	}
+	if !trinary.Equals(m.SignatureMessageFragment[:4], []int8{1, 1, 1, 0}) || len(msg.SignatureMessageFragment) != 6561 {
+		t.Fatal(m.SignatureMessageFragment[:4])
+	}
+	if m.TxHash() != msg.TxHash() {
+		t.Fatal(m.TxHash())
+	}
+}
+
+func TestEncodeInvalid(t *testing.T) {
+	table := []Message{
+		{Tag: make([]int8, 82)},
+		{Nonce: make([]int8, 82)},
+		{Value: 3 * 1e15},
+		{Ts: 1e13},
+		{CurrentIndex: -1e13},
+	}
+
+	for i, m := range table {
+		if err := m.Encode(); err == nil {
+			t.Fatal(i)
+		}
+	}
+}
+
+func TestMessageString(t *testing.T) {
+	msg, err := ParseTxBytes(msgBytes()[:txnPacketBytes])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := msg.SignatureMessageFragmentTrytes(); len(s) != trinary.SignatureMessageFragmentTrytes || strings.Trim(s, "9") != "" {
+		t.Fatal(s)
+	}
+	if s, err := msg.MessageString(); err != nil || s != "" {
+		t.Fatal(s, err)
+	}
+
+	type test struct {
+		trytes string
+		text   string
+		err    bool
+	}
+	table := []test{
+		{"RBTC9D9DCDQAEAFCCDFD9DSCFA", "Hello, World!", false},
+		{trinary.StringToTrytes("Grüße"), "Grüße", false},
+		{"MMMM", "", true}, // not a byte
+		{"LILI", "", true}, // 0xff is not UTF-8
+	}
+
+	for _, v := range table {
+		fragment := make([]int8, signatureMessageFragmentTrinarySize)
+		if _, err := trinary.TritsFromTrytes(fragment, v.trytes); err != nil {
+			t.Fatal(err)
+		}
+		msg.SignatureMessageFragment = fragment
+		if err := msg.Encode(); err != nil {
+			t.Fatal(err)
+		}
+
+		m, err := ParseTxBytes(msg.TxBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(m.SignatureMessageFragmentTrytes(), v.trytes) {
+			t.Fatal(m.SignatureMessageFragmentTrytes())
+		}
+		s, err := m.MessageString()
+		if (err != nil) != v.err || s != v.text {
+			t.Fatal(v.trytes, s, err)
+		}
+	}
+}
+
+func TestLayout(t *testing.T) {
+	if trinarySize != trinary.TransactionTrits {
+		t.Fatal(trinarySize)
+	}
+	if nonceTrinaryOffset != trinary.NonceOffset {
+		t.Fatal(nonceTrinaryOffset)
+	}
+}
@@ -7,6 +7,7 @@ type Node struct {
 	logger Logger
 	store  storage.Store
 	udp    *UDP
+	admin  *Admin
 }
 
 func New(conf Conf, store storage.Store, logger Logger) *Node {
@@ -15,17 +16,27 @@ func New(conf Conf, store storage.Store, logger Logger) *Node {
 		logger: logger,
 		store:  store,
 		udp:    NewUDP(conf.UdpHost, conf.MinWeightMagnitude, logger, store),
+		admin:  NewAdmin(conf.AdminHost, logger, store),
 	}
 }
 
+// OnStore sets a function that is called with every transaction the node stored. Must be called before Serve.
+func (node *Node) OnStore(fn func(m *Message)) {
+	node.udp.onStore = fn
+}
+
 func (node *Node) Serve() error {
 	if err := node.udp.Listen(); err != nil {
 		return err
 	}
+	if err := node.admin.Listen(); err != nil {
+		return err
+	}
 	return nil
 }
 
 func (node *Node) Shutdown() error {
 	node.udp.Close()
+	node.admin.Close()
 	return node.store.Close()
 }
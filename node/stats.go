@@ -0,0 +1,227 @@
+package node
+
+import (
+	"github.com/eaigner/igi/hash"
+	"github.com/eaigner/igi/storage"
+	"github.com/eaigner/igi/trinary"
+)
+
+// Stats combines database and tangle statistics.
+type Stats struct {
+	Storage *storage.Stats `json:"storage"`
+	Tangle  TangleStats    `json:"tangle"`
+}
+
+// TangleStats describes the stored tangle.
+type TangleStats struct {
+	Transactions int `json:"transactions"`
+	Tips         int `json:"tips"`      // transactions not referenced by any other transaction
+	Solid        int `json:"solid"`     // transactions with their complete history stored
+	Confirmed    int `json:"confirmed"` // transactions referenced by a stored milestone
+}
+
+// ReadStats collects database statistics and walks all stored transactions to compute tangle statistics.
+func ReadStats(store storage.Store) (*Stats, error) {
+	storageStats, err := store.Stats()
+	if err != nil {
+		return nil, err
+	}
+
+	tangle, err := readTangleStats(store)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Stats{Storage: storageStats, Tangle: tangle}, nil
+}
+
+const (
+	nullRef    = -1 // the null hash, referenced by the genesis
+	missingRef = -2 // a transaction that is not stored
+)
+
+const (
+	unvisited int8 = iota
+	visiting
+	solid
+	notSolid
+)
+
+// txKey is a packed transaction hash, as used for storage keys.
+type txKey [hash.SizeBytes]byte
+
+// approvees holds the trunk and branch of a transaction as transaction numbers, or nullRef or missingRef.
+type approvees [2]int32
+
+// readTangleStats numbers the stored transactions in a first pass and resolves their approvees in a second pass,
+// so only the packed keys and two numbers per transaction are held in memory.
+func readTangleStats(store storage.Store) (TangleStats, error) {
+	var stats TangleStats
+
+	index := make(map[txKey]int32)
+
+	err := store.ForEach(storage.TransactionBucket, func(k, v []byte) error {
+		var key txKey
+		if len(k) != len(key) {
+			return nil // not a transaction hash, e.g. a malformed legacy key left by the key compaction
+		}
+		copy(key[:], k)
+		index[key] = int32(len(index))
+		return nil
+	})
+
+	if err != nil {
+		return stats, err
+	}
+
+	ref := func(h hash.Hash) int32 {
+		if h.Zero() {
+			return nullRef
+		}
+		var key txKey
+		copy(key[:], h.Bytes())
+		if i, ok := index[key]; ok {
+			return i
+		}
+		return missingRef
+	}
+
+	txs := make([]approvees, len(index))
+	referenced := make([]bool, len(index))
+
+	var buf [trinary.TxTrits]int8
+
+	err = store.ForEach(storage.TransactionBucket, func(k, v []byte) error {
+		var key txKey
+		if len(k) != len(key) {
+			return nil
+		}
+		copy(key[:], k)
+		i, ok := index[key]
+		if !ok {
+			return nil // stored after the first pass
+		}
+		m, err := ParseTxBytesInto(&buf, v)
+		if err != nil {
+			return err
+		}
+		txs[i] = approvees{ref(m.Trunk), ref(m.Branch)}
+		for _, r := range txs[i] {
+			if r >= 0 {
+				referenced[r] = true
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return stats, err
+	}
+
+	var milestones []int32
+
+	err = store.ForEach(storage.MilestoneBucket, func(k, v []byte) error {
+		h, err := hash.FromBytes(v)
+		if err != nil {
+			return err
+		}
+		if r := ref(h); r >= 0 {
+			milestones = append(milestones, r)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return stats, err
+	}
+
+	stats.Transactions = len(txs)
+
+	for _, r := range referenced {
+		if !r {
+			stats.Tips++
+		}
+	}
+
+	state := make([]int8, len(txs))
+
+	for i := range txs {
+		if isSolid(int32(i), txs, state) {
+			stats.Solid++
+		}
+	}
+
+	stats.Confirmed = countConfirmed(milestones, txs)
+
+	return stats, nil
+}
+
+// isSolid checks if a transaction and all of its approvees down to the genesis (null hash) are stored.
+// Results are memoized in state. The tangle is walked with an explicit stack, since it can be very deep.
+func isSolid(tx int32, txs []approvees, state []int8) bool {
+	stack := []int32{tx}
+
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+
+		if state[i] == solid || state[i] == notSolid {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		done := true
+		result := true
+
+		for _, r := range txs[i] {
+			switch {
+			case r == nullRef:
+			case r == missingRef:
+				result = false
+			case state[r] == unvisited:
+				stack = append(stack, r)
+				done = false
+			case state[r] != solid:
+				result = false
+			}
+		}
+
+		state[i] = visiting
+
+		if done {
+			if result {
+				state[i] = solid
+			} else {
+				state[i] = notSolid
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	return state[tx] == solid
+}
+
+// countConfirmed counts the transactions referenced directly or indirectly by the milestones.
+func countConfirmed(milestones []int32, txs []approvees) int {
+	confirmed := make([]bool, len(txs))
+	stack := milestones
+	n := 0
+
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if confirmed[i] {
+			continue
+		}
+		confirmed[i] = true
+		n++
+
+		for _, r := range txs[i] {
+			if r >= 0 && !confirmed[r] {
+				stack = append(stack, r)
+			}
+		}
+	}
+
+	return n
+}
@@ -0,0 +1,95 @@
+package node
+
+import (
+	"os"
+	"testing"
+
+	"github.com/eaigner/igi/storage"
+)
+
+func TestReadStats(t *testing.T) {
+	dbPath := "test_stats.db"
+
+	os.Remove(dbPath)
+
+	store, err := storage.NewBoltStore(dbPath, storage.Mainnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	defer os.Remove(dbPath)
+
+	msg, err := ParseUdpBytes(msgBytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := msg.Store(store); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := ReadStats(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Storage.Buckets[storage.TransactionBucket.String()].Keys != 1 {
+		t.Fatal(stats.Storage.Buckets)
+	}
+
+	// The trunk of the fixture is not stored
+	if stats.Tangle != (TangleStats{Transactions: 1, Tips: 1, Solid: 0, Confirmed: 0}) {
+		t.Fatal(stats.Tangle)
+	}
+
+	// Milestone with index 1, see milestone.Store
+	if err := storage.Write(store, []byte{0, 0, 0, 1}, msg.TxHash().Bytes(), storage.MilestoneBucket); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err = ReadStats(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Tangle != (TangleStats{Transactions: 1, Tips: 1, Solid: 0, Confirmed: 1}) {
+		t.Fatal(stats.Tangle)
+	}
+}
+
+func TestIsSolid(t *testing.T) {
+	txs := []approvees{
+		{nullRef, nullRef},
+		{0, nullRef},
+		{1, 0},
+		{2, missingRef},
+		{5, nullRef},
+		{4, nullRef},
+	}
+	expect := []bool{true, true, true, false, false, false}
+
+	state := make([]int8, len(txs))
+
+	for i, v := range expect {
+		if isSolid(int32(i), txs, state) != v {
+			t.Fatal(i, !v)
+		}
+	}
+}
+
+func TestCountConfirmed(t *testing.T) {
+	txs := []approvees{
+		{nullRef, nullRef},
+		{0, nullRef},
+		{1, 0},
+		{2, missingRef},
+		{0, nullRef}, // not referenced by a milestone
+	}
+
+	if n := countConfirmed([]int32{2}, txs); n != 3 {
+		t.Fatal(n)
+	}
+	if n := countConfirmed([]int32{3, 2}, txs); n != 4 {
+		t.Fatal(n)
+	}
+	if n := countConfirmed(nil, txs); n != 0 {
+		t.Fatal(n)
+	}
+}
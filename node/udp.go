@@ -4,9 +4,10 @@ import (
 	"github.com/eaigner/igi/queue"
 	"github.com/eaigner/igi/storage"
 	"net"
+	"runtime"
+	"sync"
 
 	"github.com/eaigner/igi/hash"
-	"github.com/eaigner/igi/trinary"
 )
 
 type UDP struct {
@@ -19,9 +20,23 @@ type UDP struct {
 	txCache      *Cache
 	receiveQueue *queue.WeightQueue
 	replyQueue   *queue.WeightQueue
-	closed       bool
+	packets      chan packet
+	workers      sync.WaitGroup
+	loops        sync.WaitGroup // reply and receive loops
+	onStore      func(m *Message)
 }
 
+// packet is a raw UDP packet waiting to be processed by an ingest worker.
+type packet struct {
+	b    []byte
+	addr *net.UDPAddr
+}
+
+const (
+	packetQueueSize = 4096
+	maxPacketBatch  = hash.BCTBatchSize
+)
+
 func NewUDP(host string, minWeightMag int, logger Logger, store storage.Store) *UDP {
 	return &UDP{
 		host:         host,
@@ -32,7 +47,7 @@ func NewUDP(host string, minWeightMag int, logger Logger, store storage.Store) *
 		txCache:      NewCache(1024),
 		receiveQueue: queue.NewWeightQueue(1024),
 		replyQueue:   queue.NewWeightQueue(1024),
-		closed:       false,
+		packets:      make(chan packet, packetQueueSize),
 	}
 }
 
@@ -49,19 +64,32 @@ func (udp *UDP) Listen() error {
 	udp.logger.Printf("listening on udp://%v", addr)
 	udp.conn = conn
 
+	udp.loops.Add(2)
 	go udp.replyLoop()
 	go udp.receiveLoop()
+
+	for i := 0; i < runtime.GOMAXPROCS(0); i++ {
+		udp.workers.Add(1)
+		go udp.ingestLoop()
+	}
+
 	go udp.read(conn)
 
 	return nil
 }
 
+// Close stops reading and closes the queues, so ingest workers blocked on a full queue return.
+// Returns after all goroutines exited, so the store is no longer used afterwards.
 func (udp *UDP) Close() {
 	udp.conn.Close()
-	udp.closed = true
+	udp.receiveQueue.Close()
+	udp.replyQueue.Close()
 	<-udp.done
+	udp.loops.Wait()
 }
 
+// read only copies packets off the socket and hands them to the ingest workers,
+// so bursts do not stall reads and cause the kernel to drop packets.
 func (udp *UDP) read(conn *net.UDPConn) {
 	var buf [1024 * 10]byte
 	for {
@@ -69,69 +97,128 @@ func (udp *UDP) read(conn *net.UDPConn) {
 		if err != nil {
 			udp.logger.Printf("error reading UDP packet: %v", err)
 			break
-		} else {
-			udp.handleMessage(buf[:n], addr)
+		}
+		b := make([]byte, n)
+		copy(b, buf[:n])
+		select {
+		case udp.packets <- packet{b, addr}:
+		default:
+			udp.logger.Printf("ingest queue full, dropping packet from %v", addr)
 		}
 	}
+	close(udp.packets)
+	udp.workers.Wait()
 	udp.logger.Printf("udp server closed")
 	udp.done <- true
 }
 
+// ingestLoop parses, hashes and validates packets. Packets that are already queued are hashed
+// together in one batch.
+func (udp *UDP) ingestLoop() {
+	defer udp.workers.Done()
+
+	batch := make([]packet, 0, maxPacketBatch)
+
+	for p := range udp.packets {
+		batch = append(batch[:0], p)
+	drain:
+		for len(batch) < maxPacketBatch {
+			select {
+			case p, ok := <-udp.packets:
+				if !ok {
+					break drain
+				}
+				batch = append(batch, p)
+			default:
+				break drain
+			}
+		}
+		udp.handlePackets(batch)
+	}
+}
+
+func (udp *UDP) handlePackets(batch []packet) {
+	msgs := make([]*Message, 0, len(batch))
+	addrs := make([]*net.UDPAddr, 0, len(batch))
+
+	for _, p := range batch {
+		udp.logger.Printf("message from UDP neighbor: %v", p.addr)
+
+		msg, err := ParseUdpBytes(p.b)
+		if err != nil {
+			udp.logger.Printf("error parsing message: %v", err)
+			continue // drop
+		}
+		msgs = append(msgs, msg)
+		addrs = append(addrs, p.addr)
+	}
+
+	HashMessages(msgs)
+
+	for i, msg := range msgs {
+		udp.handleMessage(msg, addrs[i])
+	}
+}
+
 func (udp *UDP) replyLoop() {
-	for !udp.closed {
-		var _ = udp.replyQueue.Pop().(*replyItem)
+	defer udp.loops.Done()
+
+	for {
+		if _, ok := udp.replyQueue.Pop().(*replyItem); !ok {
+			return // closed
+		}
 		// TODO: do something with item, implement "Node.replyToRequest"
 	}
 }
 
 func (udp *UDP) receiveLoop() {
-	for !udp.closed {
-		item := udp.receiveQueue.Pop().(*receiveItem)
+	defer udp.loops.Done()
+
+	for {
+		item, ok := udp.receiveQueue.Pop().(*receiveItem)
+		if !ok {
+			return // closed
+		}
 
 		// TODO: do something with item, implement "Node.processReceivedData"
 		if err := item.msg.Store(udp.store); err != nil {
 			// TODO: handle error
 			udp.logger.Printf("message not stored: %v", err)
 		} else {
-			udp.logger.Printf("message stored %v", item.msg.TxDigestHex())
+			udp.logger.Printf("message stored %v", item.msg.TxHash())
+			if udp.onStore != nil {
+				udp.onStore(item.msg)
+			}
 			// TODO: was stored, broadcast
 		}
 	}
 }
 
-func (udp *UDP) handleMessage(b []byte, neighbor *net.UDPAddr) {
-	udp.logger.Printf("message from UDP neighbor: %v", neighbor)
+func (udp *UDP) handleMessage(msg *Message, neighbor *net.UDPAddr) {
+	// Check if the trailer hash is the same as the current message transaction hash.
+	// If it's the same, request a random tip by sending the zero hash.
+	// This is done first, since hashes are cached on the message, which must not be
+	// modified after it was handed to the receive loop.
+	requestedHash := msg.TrailerHash()
+	txHash := msg.TxHash()
 
-	msg, err := ParseUdpBytes(b)
-	if err != nil {
-		udp.logger.Printf("error parsing message: %v", err)
-		return // drop
+	if txHash == requestedHash {
+		requestedHash = hash.Hash{}
 	}
 
 	// Check if we have seen this transaction lately.
-	// []uint8 is not a valid cache key, so we use the hex digest.
-	key := msg.TxDigestHex()
-
-	_, cached := udp.txCache.Get(key)
+	_, cached := udp.txCache.Get(txHash)
 
 	if !cached {
 		if err := msg.Validate(udp.minWeightMag); err != nil {
 			udp.logger.Printf("invalid message: %v", err)
 			return // drop
 		}
-		udp.txCache.Add(key, msg.TxHash())
-		udp.receiveQueue.Push(&receiveItem{msg, neighbor}, hash.WeightMagnitude(msg.TxHash()))
-	}
-
-	// Check if the trailer hash is the same as the current message transaction hash.
-	// If it's the same, request a random tip by sending the zero hash.
-	requestedHash := msg.TrailerHash()
-
-	if trinary.Equals(msg.TxHash(), requestedHash) {
-		requestedHash = make([]int8, len(requestedHash))
+		udp.txCache.Add(txHash, struct{}{})
+		udp.receiveQueue.Push(&receiveItem{msg, neighbor}, txHash.WeightMagnitude())
 	}
 
-	udp.replyQueue.Push(&replyItem{requestedHash, neighbor}, hash.WeightMagnitude(requestedHash))
+	udp.replyQueue.Push(&replyItem{requestedHash, neighbor}, requestedHash.WeightMagnitude())
 }
 
 type receiveItem struct {
@@ -140,6 +227,6 @@ type receiveItem struct {
 }
 
 type replyItem struct {
-	requestedHash []int8
+	requestedHash hash.Hash
 	neighbor      *net.UDPAddr
 }
@@ -0,0 +1,124 @@
+package node
+
+import (
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/eaigner/igi/queue"
+	"github.com/eaigner/igi/storage"
+)
+
+func TestUDPIngest(t *testing.T) {
+	dbPath := "test_udp.db"
+
+	os.Remove(dbPath)
+
+	store, err := storage.NewBoltStore(dbPath, storage.Mainnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	defer os.Remove(dbPath)
+
+	udp := NewUDP("127.0.0.1:0", 0, NewNullLogger(), store)
+
+	if err := udp.Listen(); err != nil {
+		t.Fatal(err)
+	}
+	defer udp.Close()
+
+	conn, err := net.DialUDP("udp", nil, udp.conn.LocalAddr().(*net.UDPAddr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	b := msgBytes()
+
+	if _, err := conn.Write(b); err != nil {
+		t.Fatal(err)
+	}
+
+	msg, err := ParseUdpBytes(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 100; i++ {
+		exists, err := storage.Exists(store, msg.TxHash().Bytes(), storage.TransactionBucket)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exists {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatal("transaction was not stored")
+}
+
+func TestUDPCloseWithFullQueue(t *testing.T) {
+	dbPath := "test_udp_close.db"
+
+	os.Remove(dbPath)
+
+	store, err := storage.NewBoltStore(dbPath, storage.Mainnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	defer os.Remove(dbPath)
+
+	// The receive loop stalls on the first stored message, so workers block on the full receive queue
+	block := make(chan bool)
+
+	udp := NewUDP("127.0.0.1:0", 0, NewNullLogger(), store)
+	udp.receiveQueue = queue.NewWeightQueue(1)
+	udp.onStore = func(m *Message) {
+		<-block
+	}
+
+	if err := udp.Listen(); err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := net.DialUDP("udp", nil, udp.conn.LocalAddr().(*net.UDPAddr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	for i := 0; i < 100; i++ {
+		b := msgBytes()
+		b[0] = byte(i) // distinct transactions, so they are not cached
+		if _, err := conn.Write(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	closed := make(chan bool)
+	go func() {
+		udp.Close()
+		closed <- true
+	}()
+
+	// Close waits for the message that is still being stored
+	select {
+	case <-closed:
+		t.Fatal("closed while storing")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(block)
+
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("close blocked")
+	}
+}
@@ -0,0 +1,189 @@
+package pow
+
+import (
+	"context"
+	"errors"
+	"math/bits"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/eaigner/igi/hash"
+	"github.com/eaigner/igi/trinary"
+)
+
+const (
+	// The midstate covers all blocks but the last, only the last block containing the nonce is hashed per iteration.
+	midstateTrits   = trinary.TransactionTrits - hash.SizeTrits
+	lastBlockTrits  = trinary.TransactionTrits - midstateTrits
+	nonceBlockStart = trinary.NonceOffset - midstateTrits
+
+	// The nonce is split into the lane index of the BCT search, the worker index and a counter
+	// incremented by every worker.
+	laneTrits    = 5 // 5 balanced trits hold lanes up to 121, 4 only up to 40
+	workerTrits  = 8
+	counterStart = laneTrits + workerTrits
+	maxWorkers   = (3*3*3*3*3*3*3*3 - 1) / 2
+)
+
+var (
+	errInvalidLength = errors.New("invalid transaction length")
+	errInvalidMWM    = errors.New("invalid minimum weight magnitude")
+)
+
+// Result is the outcome of a nonce search.
+type Result struct {
+	Nonce    []int8
+	Hashes   uint64
+	Duration time.Duration
+}
+
+// Hashrate returns the number of hashes computed per second.
+func (r *Result) Hashrate() float64 {
+	if r.Duration <= 0 {
+		return 0
+	}
+	return float64(r.Hashes) / r.Duration.Seconds()
+}
+
+// Search searches for a nonce, so that the Curl-P-81 hash of the transaction trits has a weight magnitude
+// of at least mwm. On success the nonce is written to the nonce trits of trits.
+// The search runs on workers goroutines, each hashing hash.BCTBatchSize nonces at once. If workers is
+// smaller than 1, GOMAXPROCS workers are used.
+// If ctx is cancelled before a nonce was found, the context error is returned.
+func Search(ctx context.Context, trits []int8, mwm int, workers int) (*Result, error) {
+	if len(trits) != trinary.TransactionTrits {
+		return nil, errInvalidLength
+	}
+	if mwm < 0 || mwm > hash.SizeTrits {
+		return nil, errInvalidMWM
+	}
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > maxWorkers {
+		workers = maxWorkers
+	}
+
+	start := time.Now()
+
+	var curl hash.Curl
+
+	curl.Reset(hash.CurlP81)
+	curl.Absorb(trits[:midstateTrits])
+
+	var mid hash.BCTCurl
+
+	mid.LoadCurl(&curl)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		hashes uint64
+		once   sync.Once
+		nonce  []int8
+		wg     sync.WaitGroup
+	)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			if n := search(ctx, &mid, trits[midstateTrits:], mwm, worker, &hashes); n != nil {
+				once.Do(func() {
+					nonce = n
+					cancel()
+				})
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	if nonce == nil {
+		return nil, ctx.Err()
+	}
+
+	copy(trits[trinary.NonceOffset:], nonce)
+
+	return &Result{
+		Nonce:    nonce,
+		Hashes:   atomic.LoadUint64(&hashes),
+		Duration: time.Since(start),
+	}, nil
+}
+
+// search hashes the last block of a transaction with different nonces, until a nonce is found or ctx is done.
+func search(ctx context.Context, mid *hash.BCTCurl, block []int8, mwm int, worker int, hashes *uint64) []int8 {
+	var low, high [lastBlockTrits]uint64
+
+	for i, v := range block {
+		low[i], high[i] = bct(v)
+	}
+
+	nonce := make([]int8, trinary.NonceTrits)
+
+	trinary.FromInt64(nonce[laneTrits:counterStart], int64(worker)) // workers are limited to maxWorkers
+
+	// Every lane gets a different nonce prefix
+	for lane := 0; lane < hash.BCTBatchSize; lane++ {
+		trinary.FromInt64(nonce[:laneTrits], int64(lane))
+		for i, v := range nonce[:laneTrits] {
+			l, h := bct(v)
+			j := nonceBlockStart + i
+			low[j] = low[j]&^(1<<uint(lane)) | l&(1<<uint(lane))
+			high[j] = high[j]&^(1<<uint(lane)) | h&(1<<uint(lane))
+		}
+	}
+
+	for i := laneTrits; i < trinary.NonceTrits; i++ {
+		low[nonceBlockStart+i], high[nonceBlockStart+i] = bct(nonce[i])
+	}
+
+	var curl hash.BCTCurl
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		curl = *mid
+		curl.AbsorbBCT(low[:], high[:])
+
+		atomic.AddUint64(hashes, hash.BCTBatchSize)
+
+		// A trit is zero if both its low and high bit are set
+		found := ^uint64(0)
+		for i := hash.SizeTrits - mwm; i < hash.SizeTrits && found != 0; i++ {
+			l, h := curl.StateBCT(i)
+			found &= l & h
+		}
+
+		if found != 0 {
+			lane := bits.TrailingZeros64(found)
+			trinary.FromInt64(nonce[:laneTrits], int64(lane))
+			return nonce
+		}
+
+		// Only the counter trits up to the last carry change
+		n := trinary.Increment(nonce[counterStart:])
+		for i := counterStart; i < counterStart+n; i++ {
+			low[nonceBlockStart+i], high[nonceBlockStart+i] = bct(nonce[i])
+		}
+	}
+}
+
+// bct returns the binary-coded low and high word of a trit, set for all inputs.
+func bct(v int8) (low, high uint64) {
+	switch v {
+	case 1:
+		return 0, ^uint64(0)
+	case -1:
+		return ^uint64(0), 0
+	}
+	return ^uint64(0), ^uint64(0)
+}
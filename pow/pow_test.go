@@ -0,0 +1,92 @@
+package pow
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/eaigner/igi/hash"
+	"github.com/eaigner/igi/trinary"
+)
+
+func randomTransaction(seed int64) []int8 {
+	r := rand.New(rand.NewSource(seed))
+	t := make([]int8, trinary.TransactionTrits)
+	for i := range t {
+		t[i] = int8(r.Intn(3)) - 1
+	}
+	return t
+}
+
+func TestSearch(t *testing.T) {
+	for _, workers := range []int{1, 4} {
+		tx := randomTransaction(int64(workers))
+
+		res, err := Search(context.Background(), tx, 9, workers)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var curl hash.Curl
+		var h [hash.SizeTrits]int8
+
+		curl.Reset(hash.CurlP81)
+		curl.Absorb(tx)
+		curl.Squeeze(h[:])
+
+		if mwm := hash.WeightMagnitude(h[:]); mwm < 9 {
+			t.Fatal(workers, mwm)
+		}
+		if res.Hashes == 0 || res.Hashrate() <= 0 {
+			t.Fatal(res.Hashes, res.Hashrate())
+		}
+	}
+}
+
+func TestSearchCancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := Search(ctx, randomTransaction(1), hash.SizeTrits, 2)
+	if err != context.DeadlineExceeded {
+		t.Fatal(err)
+	}
+}
+
+func TestNonceLayout(t *testing.T) {
+	lanes := make(map[int64]bool)
+
+	for lane := int64(0); lane < hash.BCTBatchSize; lane++ {
+		trits := make([]int8, laneTrits)
+		if err := trinary.FromInt64(trits, lane); err != nil {
+			t.Fatal(lane, err)
+		}
+		lanes[trinary.Int64(trits)] = true
+	}
+	if len(lanes) != hash.BCTBatchSize {
+		t.Fatal(len(lanes))
+	}
+	if err := trinary.FromInt64(make([]int8, workerTrits), maxWorkers); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSearchInvalid(t *testing.T) {
+	if _, err := Search(context.Background(), make([]int8, 10), 9, 1); err != errInvalidLength {
+		t.Fatal(err)
+	}
+	if _, err := Search(context.Background(), make([]int8, trinary.TransactionTrits), -1, 1); err != errInvalidMWM {
+		t.Fatal(err)
+	}
+}
+
+func BenchmarkSearch(b *testing.B) {
+	tx := randomTransaction(1)
+
+	for i := 0; i < b.N; i++ {
+		if _, err := Search(context.Background(), tx, 12, 0); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
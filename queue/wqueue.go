@@ -10,41 +10,74 @@ import (
 // (minimum weight magnitude) would rise to the top of the priority queue, so if you want your translation to propagate
 // faster in the network you would apply more PoW, hence setting priority based on MWM.
 type WeightQueue struct {
-	q   pQueue
-	c   chan bool
-	mtx sync.Mutex
+	q     pQueue
+	slots chan bool // one value per queued item, limits the queue length
+	items chan bool // one value per item that can be popped
+	done  chan bool
+	once  sync.Once
+	mtx   sync.Mutex
 }
 
 // NewWeightQueue creates a new weighted queue. Items in the queue are ordered by weight (heaviest first).
 func NewWeightQueue(maxLen int) *WeightQueue {
 	return &WeightQueue{
-		q: make(pQueue, 0),
-		c: make(chan bool, maxLen),
+		q:     make(pQueue, 0),
+		slots: make(chan bool, maxLen),
+		items: make(chan bool, maxLen),
+		done:  make(chan bool),
 	}
 }
 
 // Push pushes a new weighted value to the queue. If the queue is full, this call blocks until the queue drops below
-// its own max length.
+// its own max length. Returns false without pushing if the queue was closed.
 func (q *WeightQueue) Push(value interface{}, weight int) bool {
+	select {
+	case <-q.done:
+		return false
+	default:
+	}
+	select {
+	case q.slots <- true:
+	case <-q.done:
+		return false
+	}
 	q.mtx.Lock()
 	heap.Push(&q.q, &pqItem{
 		value:    value,
 		priority: weight,
 	})
 	q.mtx.Unlock()
-	q.c <- true
+	q.items <- true
 	return true
 }
 
 // Pop pops an item from the queue. If no item is present the call blocks until a new item was pushed.
+// Returns nil if the queue was closed.
 func (q *WeightQueue) Pop() interface{} {
-	<-q.c
+	select {
+	case <-q.done:
+		return nil
+	default:
+	}
+	select {
+	case <-q.items:
+	case <-q.done:
+		return nil
+	}
 	q.mtx.Lock()
 	item := heap.Pop(&q.q).(*pqItem)
 	q.mtx.Unlock()
+	<-q.slots
 	return item.value
 }
 
+// Close unblocks all pending and future calls to Push and Pop. Queued items are discarded.
+func (q *WeightQueue) Close() {
+	q.once.Do(func() {
+		close(q.done)
+	})
+}
+
 type pqItem struct {
 	value    interface{}
 	priority int
@@ -2,6 +2,7 @@ package queue
 
 import (
 	"testing"
+	"time"
 )
 
 func TestWeightQueue(t *testing.T) {
@@ -20,3 +21,43 @@ func TestWeightQueue(t *testing.T) {
 		}
 	}
 }
+
+func TestWeightQueueClose(t *testing.T) {
+	q := NewWeightQueue(1)
+	q.Push(1, 1)
+
+	pushed := make(chan bool)
+	go func() {
+		pushed <- q.Push(2, 2) // blocks, the queue is full
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	q.Close()
+
+	select {
+	case ok := <-pushed:
+		if ok {
+			t.Fatal(ok)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("push still blocked after close")
+	}
+
+	if v := q.Pop(); v != nil {
+		t.Fatal(v)
+	}
+	if q.Push(3, 3) {
+		t.Fatal()
+	}
+	q.Close()
+
+	// Pushes are rejected after close, even if there are free slots
+	q = NewWeightQueue(16)
+	q.Close()
+
+	for i := 0; i < 16; i++ {
+		if q.Push(i, i) {
+			t.Fatal(i)
+		}
+	}
+}
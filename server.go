@@ -1,27 +1,53 @@
 package main
 
 import (
+	"compress/gzip"
+	"encoding/json"
 	"flag"
+	"fmt"
+	"github.com/eaigner/igi/hash"
+	"github.com/eaigner/igi/milestone"
+	"github.com/eaigner/igi/signing"
 	"github.com/eaigner/igi/storage"
+	"github.com/eaigner/igi/trinary"
+	"io"
 	"log"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 
 	gonode "github.com/eaigner/igi/node"
 )
 
-var conf gonode.Conf
+var (
+	conf       gonode.Conf
+	exportPath string
+	importPath string
+	printStats bool
+	cooAddress string
+	cooDepth   int
+	cooSec     int
+	cooKerl    bool
+)
 
 func init() {
 	flag.StringVar(&conf.HttpHost, "p", ":15100", "http server address")
 	flag.StringVar(&conf.UdpHost, "u", ":15200", "udp socket address")
 	flag.StringVar(&conf.TcpHost, "t", ":15300", "tcp socket address")
+	flag.StringVar(&conf.AdminHost, "a", "127.0.0.1:15400", "admin http server address, e.g. GET /backup streams a database snapshot. Empty disables the admin server")
 	flag.StringVar(&conf.DbPath, "db", "tangle.db", "tangle database path")
 	flag.BoolVar(&conf.Debug, "debug", false, "turn on debug mode")
 	flag.BoolVar(&conf.Testnet, "testnet", false, "use testnet")
 	flag.Var(&conf.Neighbors, "n", "single neighbor node URL, flag can be used multiple times")
 	flag.IntVar(&conf.MinWeightMagnitude, "w", 14, "min weight magnitude")
+	flag.StringVar(&exportPath, "export", "", "export all transactions as trytes to a file (gzip'd if it ends with .gz) and exit")
+	flag.StringVar(&importPath, "import", "", "import transaction trytes from a file (gzip'd if it ends with .gz) and exit")
+	flag.BoolVar(&printStats, "stats", false, "print database and tangle statistics as JSON and exit")
+	flag.StringVar(&cooAddress, "coo", "", "coordinator address trytes, with or without checksum, milestones are not tracked if empty")
+	flag.IntVar(&cooDepth, "coo-depth", 20, "coordinator merkle tree depth")
+	flag.IntVar(&cooSec, "coo-security", 1, "coordinator key security level")
+	flag.BoolVar(&cooKerl, "coo-kerl", false, "coordinator signs milestones with Kerl instead of Curl-P-27")
 	flag.Parse()
 }
 
@@ -43,14 +69,43 @@ func main() {
 		done <- true
 	}()
 
-	db, err := storage.NewBoltStore(conf.DbPath)
+	network := storage.Mainnet
+
+	if conf.Testnet {
+		network = storage.Testnet
+	}
+
+	db, err := storage.NewBoltStore(conf.DbPath, network)
 
 	if err != nil {
 		panic(err)
 	}
 
+	var tracker *milestone.Tracker
+
+	if cooAddress != "" {
+		coo, err := newCoordinator()
+		if err != nil {
+			panic(err)
+		}
+		tracker = milestone.NewTracker(coo, db, logger)
+	}
+
+	if exportPath != "" || importPath != "" || printStats {
+		defer db.Close()
+		if err := runTangleCommand(db, tracker); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	node := gonode.New(conf, db, logger)
 
+	if tracker != nil {
+		node.OnStore(tracker.Process)
+	}
+
 	logger.Println("starting node...")
 
 	if err := node.Serve(); err != nil {
@@ -68,3 +123,109 @@ func main() {
 
 	logger.Println("node stopped")
 }
+
+func newCoordinator() (*milestone.Coordinator, error) {
+	if !trinary.ValidTrytes(cooAddress) {
+		return nil, fmt.Errorf("invalid coordinator address %q: not trytes", cooAddress)
+	}
+
+	trytes, err := signing.StripChecksum(cooAddress)
+	if err != nil {
+		return nil, fmt.Errorf("invalid coordinator address %q: %v", cooAddress, err)
+	}
+
+	address, err := hash.FromTrytes(trytes)
+	if err != nil {
+		return nil, err
+	}
+
+	sponge := signing.NewCurlP27()
+
+	if cooKerl {
+		sponge = signing.NewKerl()
+	}
+
+	return &milestone.Coordinator{
+		Address:  address,
+		Depth:    cooDepth,
+		Security: cooSec,
+		Sponge:   sponge,
+	}, nil
+}
+
+func runTangleCommand(db storage.Store, tracker *milestone.Tracker) error {
+	if exportPath != "" {
+		f, err := os.Create(exportPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		var w io.WriteCloser = f
+
+		if filepath.Ext(exportPath) == ".gz" {
+			w = gzip.NewWriter(f)
+		}
+
+		n, err := gonode.Export(w, db)
+		if err != nil {
+			return err
+		}
+		if err := w.Close(); err != nil {
+			return err
+		}
+
+		fmt.Printf("exported %d transactions\n", n)
+	}
+
+	if importPath != "" {
+		f, err := os.Open(importPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		var r io.Reader = f
+
+		if filepath.Ext(importPath) == ".gz" {
+			gz, err := gzip.NewReader(f)
+			if err != nil {
+				return err
+			}
+			defer gz.Close()
+			r = gz
+		}
+
+		imported, skipped, err := gonode.Import(r, db, conf.MinWeightMagnitude)
+
+		fmt.Printf("imported %d transactions, skipped %d existing\n", imported, skipped)
+
+		if err != nil {
+			return err
+		}
+
+		if tracker != nil {
+			n, err := tracker.Scan()
+			if err != nil {
+				return err
+			}
+			fmt.Printf("found %d milestones\n", n)
+		}
+	}
+
+	if printStats {
+		stats, err := gonode.ReadStats(db)
+		if err != nil {
+			return err
+		}
+
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+
+		if err := enc.Encode(stats); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
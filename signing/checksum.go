@@ -0,0 +1,67 @@
+package signing
+
+import (
+	"errors"
+
+	"github.com/eaigner/igi/hash"
+	"github.com/eaigner/igi/trinary"
+)
+
+const (
+	AddressTrytes             = hash.SizeTrits / tritsPerTryte
+	ChecksumTrytes            = 9
+	AddressWithChecksumTrytes = AddressTrytes + ChecksumTrytes
+)
+
+var (
+	errInvalidAddress  = errors.New("address must be 81 or 90 trytes")
+	errInvalidChecksum = errors.New("invalid address checksum")
+)
+
+// Checksum computes the checksum of an 81 tryte address, which are the last 9 trytes of its Kerl hash.
+func Checksum(address string) (string, error) {
+	if len(address) != AddressTrytes {
+		return "", errInvalidAddress
+	}
+
+	t := make([]int8, hash.SizeTrits)
+	if _, err := trinary.TritsFromTrytes(t, address); err != nil {
+		return "", err
+	}
+
+	h := make([]int8, hash.SizeTrits)
+	NewKerl().hash(h, t)
+
+	return trinary.Trytes(h[hash.SizeTrits-ChecksumTrytes*tritsPerTryte:])
+}
+
+// AddChecksum appends the checksum to an 81 tryte address.
+func AddChecksum(address string) (string, error) {
+	checksum, err := Checksum(address)
+	if err != nil {
+		return "", err
+	}
+	return address + checksum, nil
+}
+
+// StripChecksum verifies and removes the checksum of a 90 tryte address.
+// 81 tryte addresses without checksum are returned unchanged.
+func StripChecksum(address string) (string, error) {
+	switch len(address) {
+	case AddressTrytes:
+		if _, err := Checksum(address); err != nil {
+			return "", err
+		}
+		return address, nil
+	case AddressWithChecksumTrytes:
+		checksum, err := Checksum(address[:AddressTrytes])
+		if err != nil {
+			return "", err
+		}
+		if checksum != address[AddressTrytes:] {
+			return "", errInvalidChecksum
+		}
+		return address[:AddressTrytes], nil
+	}
+	return "", errInvalidAddress
+}
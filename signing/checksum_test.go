@@ -0,0 +1,59 @@
+package signing
+
+import "testing"
+
+func TestChecksum(t *testing.T) {
+	type test struct {
+		address  string
+		checksum string
+	}
+	table := []test{
+		{"CLAAFXEY9AHHCSZCXNKDRZEJHIAFVKYORWNOZAGFPAZYNTSLCXUAG9WBSXBRXYEDPVPLXYVDCBCEKRUBD", "OHHJEQVCY"},
+		{"VAKOBMBWEMHGZPF9VXSK9SYBJQBSMPZBIVDGHQFDZWGK9UKLPMLREXGGTUTLAYKJJWTIXW9OFQBZGFAJB", "RDYEAVFLX"},
+		{"999999999999999999999999999999999999999999999999999999999999999999999999999999999", "A9BEONKZW"},
+	}
+
+	for _, v := range table {
+		withChecksum, err := AddChecksum(v.address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if withChecksum != v.address+v.checksum {
+			t.Fatal(withChecksum)
+		}
+
+		for _, s := range []string{v.address, withChecksum} {
+			address, err := StripChecksum(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if address != v.address {
+				t.Fatal(address)
+			}
+		}
+	}
+}
+
+func TestStripChecksumInvalid(t *testing.T) {
+	const address = "CLAAFXEY9AHHCSZCXNKDRZEJHIAFVKYORWNOZAGFPAZYNTSLCXUAG9WBSXBRXYEDPVPLXYVDCBCEKRUBD"
+
+	type test struct {
+		in  string
+		err error
+	}
+	table := []test{
+		{address + "OHHJEQVCZ", errInvalidChecksum},
+		{"DLAAFXEY9AHHCSZCXNKDRZEJHIAFVKYORWNOZAGFPAZYNTSLCXUAG9WBSXBRXYEDPVPLXYVDCBCEKRUBDOHHJEQVCY", errInvalidChecksum}, // typo
+		{address[:80], errInvalidAddress},
+		{address + "OHHJ", errInvalidAddress},
+	}
+
+	for _, v := range table {
+		if _, err := StripChecksum(v.in); err != v.err {
+			t.Fatal(v.in, err)
+		}
+	}
+	if _, err := StripChecksum(address[:80] + "a"); err == nil {
+		t.Fatal("expected invalid trytes")
+	}
+}
@@ -0,0 +1,109 @@
+package signing
+
+import (
+	"errors"
+
+	"github.com/eaigner/igi/hash"
+	"github.com/eaigner/igi/trinary"
+)
+
+const (
+	keySegmentHashRounds = maxTryteValue - minTryteValue // 26
+)
+
+var (
+	errInvalidSeed          = errors.New("seed must be 243 trits")
+	errInvalidSecurityLevel = errors.New("security level must be between 1 and 3")
+	errInvalidIndex         = errors.New("key index must not be negative")
+)
+
+// Subseed derives the subseed for a key index by adding index to the seed and hashing the sum.
+func Subseed(s Sponge, seed []int8, index int64) ([]int8, error) {
+	if len(seed) != hash.SizeTrits {
+		return nil, errInvalidSeed
+	}
+	if index < 0 {
+		return nil, errInvalidIndex
+	}
+
+	t := make([]int8, hash.SizeTrits)
+	if err := trinary.FromInt64(t, index); err != nil {
+		return nil, err
+	}
+	trinary.Add(t, t, seed)
+
+	subseed := make([]int8, hash.SizeTrits)
+	s.hash(subseed, t)
+
+	return subseed, nil
+}
+
+// Key derives a private key with securityLevel fragments by squeezing the sponge after absorbing subseed.
+func Key(s Sponge, subseed []int8, securityLevel int) ([]int8, error) {
+	if len(subseed) != hash.SizeTrits {
+		return nil, errInvalidSeed
+	}
+	if securityLevel < 1 || securityLevel > MaxSecurityLevel {
+		return nil, errInvalidSecurityLevel
+	}
+
+	key := make([]int8, securityLevel*FragmentTrits)
+	s.hash(key, subseed)
+
+	return key, nil
+}
+
+// Digests computes the digest of every key fragment. Every segment is hashed 26 times,
+// then the hashed segments of a fragment are hashed into its digest.
+func Digests(s Sponge, key []int8) []int8 {
+	fragments := len(key) / FragmentTrits
+	digests := make([]int8, fragments*hash.SizeTrits)
+	buf := make([]int8, FragmentTrits)
+
+	for i := 0; i < fragments; i++ {
+		copy(buf, key[i*FragmentTrits:(i+1)*FragmentTrits])
+
+		for j := 0; j < FragmentSegments; j++ {
+			segment := buf[j*hash.SizeTrits : (j+1)*hash.SizeTrits]
+			for k := 0; k < keySegmentHashRounds; k++ {
+				s.hash(segment, segment)
+			}
+		}
+
+		s.hash(digests[i*hash.SizeTrits:(i+1)*hash.SizeTrits], buf)
+	}
+
+	return digests
+}
+
+// SignatureFragment signs a section of the normalized bundle hash with a key fragment.
+// Every key segment is hashed 13 minus the normalized tryte value times.
+func SignatureFragment(s Sponge, normalizedSection []int8, keyFragment []int8) []int8 {
+	signature := make([]int8, FragmentTrits)
+	copy(signature, keyFragment)
+
+	for i := 0; i < FragmentSegments; i++ {
+		segment := signature[i*hash.SizeTrits : (i+1)*hash.SizeTrits]
+		for j := 0; j < maxTryteValue-int(normalizedSection[i]); j++ {
+			s.hash(segment, segment)
+		}
+	}
+
+	return signature
+}
+
+// GenerateAddress derives the address of a seed for a key index and security level, using Kerl.
+func GenerateAddress(seed []int8, index int64, securityLevel int) ([]int8, error) {
+	s := NewKerl()
+
+	subseed, err := Subseed(s, seed, index)
+	if err != nil {
+		return nil, err
+	}
+	key, err := Key(s, subseed, securityLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	return Address(s, Digests(s, key)), nil
+}
@@ -0,0 +1,82 @@
+package signing
+
+import (
+	"testing"
+
+	"github.com/eaigner/igi/trinary"
+)
+
+const seed = "ZLNM9UHJWKTTDEZOTH9CXDEIFUJQCIACDPJIXPOWBDW9LTBHC9AQRIXTIHYLIIURLZCXNSTGNIVC9ISVB"
+
+func TestGenerateAddress(t *testing.T) {
+	type test struct {
+		index         int64
+		securityLevel int
+		subseed       string
+		key           string // first 81 trytes
+		address       string
+	}
+	table := []test{
+		{0, 2, "CEFLDDLMF9TO9ZLLTYXIPVFIJKAOFRIQLGNYIDZCTDYSWMNXPYNGFAKHQDY9ABGGQZHEFTXKWKWZXEIUD", "D9DWVXXXMGBR9BKHQMMRTQIQROKTLOZNNYHHETDLHVE9FUIBGLEVSTHMJHNHXRRYWHBLNUICBOQHVGBRD", "CLAAFXEY9AHHCSZCXNKDRZEJHIAFVKYORWNOZAGFPAZYNTSLCXUAG9WBSXBRXYEDPVPLXYVDCBCEKRUBD"},
+		{1, 1, "PIOVKH9GTWJYTYLUXIXOFSKCNABQFN9MPXJERMXZVWCDGFTABEVZIIXDM9YQ9LSGKFGVLZKTYJRSONDGX", "ZOSQHGQAVPDJEYP9ALJLSTEJXLXTCJWBNX9BBSFQQNIIPFGLLR9PXDYPJPHKUTUFHVYZZPRKYATAB9IJD", "VAKOBMBWEMHGZPF9VXSK9SYBJQBSMPZBIVDGHQFDZWGK9UKLPMLREXGGTUTLAYKJJWTIXW9OFQBZGFAJB"},
+		{7, 3, "VRNZVQGRUXEMEMWZVKKHJTKXRFSJMHFJKBIEEENJJFOYVBYWXCNZZH9FZVBZDGKSMFMCATIJMXHCGREQX", "MVQGRIRATJYEEHRBBPEDGZTV9NSBJLVTYSRVQQKLRBORIHW99GF99NAFZZNNCVFCQBJROUTOAQROQDGOD", "DJOV9ECPUVCNZAYUOZNTEIFCAHBOYGWWKKNHYPRBYTNZEXJNACBHPFAGTTVJCWSST9UCPMHUUALEBWSUY"},
+		{1000, 2, "TBIAEKJEXJNIWHPWVWMNYORNCZUFWIEXRFFITYQPLAKOGFCKQY9CYNGHWBAKQSTOOCGEIJACSFWXYOWHD", "WIIXHVUDOPL9AH9AFCATNRHMSCCSSYLWVRGALLXPYKHDGYDLFDITIR9SQMRGSLAYUXQEQIE9JVKVPKLUD", "PLBFFLYWTGMKXOWYZGYGYGFPQWHLWMKELPDYLJ9AVRNUBGIZCIZOHEBXIXTTSWVHPWPYNKQZBTCEAZOUB"},
+	}
+
+	s := NewKerl()
+
+	for _, v := range table {
+		subseed, err := Subseed(s, trits(t, seed), v.index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if x, _ := trinary.Trytes(subseed); x != v.subseed {
+			t.Fatal(v.index, x)
+		}
+
+		key, err := Key(s, subseed, v.securityLevel)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(key) != v.securityLevel*FragmentTrits {
+			t.Fatal(len(key))
+		}
+		if x, _ := trinary.Trytes(key[:243]); x != v.key {
+			t.Fatal(v.index, x)
+		}
+
+		address, err := GenerateAddress(trits(t, seed), v.index, v.securityLevel)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if x, _ := trinary.Trytes(address); x != v.address {
+			t.Fatal(v.index, x)
+		}
+	}
+
+	if _, err := GenerateAddress(trits(t, seed), 0, 4); err != errInvalidSecurityLevel {
+		t.Fatal(err)
+	}
+}
+
+func TestSignatureFragment(t *testing.T) {
+	s := NewKerl()
+	bundleHash := trits(t, "VAJOHANFEOTRSIPCLG9MIPENDFPLQQUGSBLBHMKZ9XVCUSWIKJOOHSPWJAXVLPTAKMPURYAYD9ONODVOW")
+	normalized := NormalizeBundleHash(bundleHash)
+
+	subseed, _ := Subseed(s, trits(t, seed), 3)
+	key, _ := Key(s, subseed, 2)
+	address := Address(s, Digests(s, key))
+
+	fragments := [][]int8{
+		SignatureFragment(s, normalized[:27], key[:FragmentTrits]),
+		SignatureFragment(s, normalized[27:54], key[FragmentTrits:]),
+	}
+
+	if !ValidateSignatures(s, address, fragments, bundleHash) {
+		t.Fatal("signature should be valid")
+	}
+	if ValidateSignatures(s, address, fragments[:1], bundleHash) {
+		t.Fatal("signature with missing fragment should be invalid")
+	}
+}
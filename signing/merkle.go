@@ -0,0 +1,28 @@
+package signing
+
+import "github.com/eaigner/igi/hash"
+
+// MerkleRoot computes the root of a Merkle tree from a leaf at index and the siblings on its path,
+// which are depth hashes ordered from the leaf up. At every level the node with the lower index
+// is absorbed first.
+func MerkleRoot(s Sponge, leaf []int8, siblings []int8, index uint64, depth int) []int8 {
+	node := make([]int8, hash.SizeTrits)
+	copy(node, leaf)
+
+	buf := make([]int8, 2*hash.SizeTrits)
+
+	for i := 0; i < depth; i++ {
+		sibling := siblings[i*hash.SizeTrits : (i+1)*hash.SizeTrits]
+		if index&1 == 0 {
+			copy(buf, node)
+			copy(buf[hash.SizeTrits:], sibling)
+		} else {
+			copy(buf, sibling)
+			copy(buf[hash.SizeTrits:], node)
+		}
+		s.hash(node, buf)
+		index >>= 1
+	}
+
+	return node
+}
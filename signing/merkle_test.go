@@ -0,0 +1,46 @@
+package signing
+
+import (
+	"testing"
+
+	"github.com/eaigner/igi/hash"
+	"github.com/eaigner/igi/trinary"
+)
+
+func TestMerkleRoot(t *testing.T) {
+	for _, s := range []Sponge{NewCurlP27(), NewKerl()} {
+		leaves := make([][]int8, 4)
+		for i := range leaves {
+			leaves[i] = make([]int8, hash.SizeTrits)
+			leaves[i][i] = 1
+		}
+
+		pair := func(a, b []int8) []int8 {
+			h := make([]int8, hash.SizeTrits)
+			s.hash(h, append(append([]int8{}, a...), b...))
+			return h
+		}
+
+		left := pair(leaves[0], leaves[1])
+		right := pair(leaves[2], leaves[3])
+		root := pair(left, right)
+
+		siblings := [][]int8{
+			append(append([]int8{}, leaves[1]...), right...),
+			append(append([]int8{}, leaves[0]...), right...),
+			append(append([]int8{}, leaves[3]...), left...),
+			append(append([]int8{}, leaves[2]...), left...),
+		}
+
+		for i, leaf := range leaves {
+			if !trinary.Equals(MerkleRoot(s, leaf, siblings[i], uint64(i), 2), root) {
+				t.Fatal(s.Mode, i)
+			}
+		}
+
+		// Wrong index
+		if trinary.Equals(MerkleRoot(s, leaves[0], siblings[0], 1, 2), root) {
+			t.Fatal(s.Mode)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package signing
+
+import (
+	"github.com/eaigner/igi/hash"
+	"github.com/eaigner/igi/trinary"
+)
+
+const (
+	FragmentTrits     = trinary.SignatureMessageFragmentTrits // trits in a key or signature fragment
+	FragmentSegments  = FragmentTrits / hash.SizeTrits        // 243 trit segments per fragment
+	MaxSecurityLevel  = 3
+	maxTryteValue     = 13
+	minTryteValue     = -maxTryteValue
+	tritsPerTryte     = 3
+	normalizedTrytes  = hash.SizeTrits / tritsPerTryte
+	normalizedSection = normalizedTrytes / MaxSecurityLevel
+)
+
+// Sponge pairs a sponge with the mode it is reset to before every hash.
+type Sponge struct {
+	hash.Sponge
+	Mode int
+}
+
+// NewKerl returns the Kerl sponge used for addresses and signatures.
+func NewKerl() Sponge {
+	return Sponge{new(hash.Kerl), 0}
+}
+
+// NewCurlP27 returns the Curl-P-27 sponge used for legacy coordinator signatures.
+func NewCurlP27() Sponge {
+	return Sponge{new(hash.Curl), hash.CurlP27}
+}
+
+// hash resets the sponge, absorbs src and squeezes len(dst) trits into dst.
+func (s Sponge) hash(dst, src []int8) {
+	s.Reset(s.Mode)
+	s.Absorb(src)
+	s.Squeeze(dst)
+}
+
+// NormalizeBundleHash converts a bundle hash into 81 tryte values in [-13, 13], where every
+// section of 27 values sums to zero. Each section is used to sign one key fragment.
+func NormalizeBundleHash(bundleHash []int8) []int8 {
+	normalized := make([]int8, normalizedTrytes)
+
+	for i := range normalized {
+		t := bundleHash[i*tritsPerTryte : (i+1)*tritsPerTryte]
+		normalized[i] = t[0] + t[1]*3 + t[2]*9
+	}
+
+	for i := 0; i < MaxSecurityLevel; i++ {
+		section := normalized[i*normalizedSection : (i+1)*normalizedSection]
+		sum := 0
+
+		for _, v := range section {
+			sum += int(v)
+		}
+		for ; sum > 0; sum-- {
+			for j := range section {
+				if section[j] > minTryteValue {
+					section[j]--
+					break
+				}
+			}
+		}
+		for ; sum < 0; sum++ {
+			for j := range section {
+				if section[j] < maxTryteValue {
+					section[j]++
+					break
+				}
+			}
+		}
+	}
+
+	return normalized
+}
+
+// Digest computes the key digest of a signature fragment, given the section of the normalized bundle hash
+// that was signed. Every segment is hashed up to the corresponding segment of the private key digest.
+func Digest(s Sponge, normalizedSection []int8, signatureFragment []int8) []int8 {
+	buf := make([]int8, FragmentTrits)
+	copy(buf, signatureFragment)
+
+	for i := 0; i < FragmentSegments; i++ {
+		segment := buf[i*hash.SizeTrits : (i+1)*hash.SizeTrits]
+		for j := 0; j < int(normalizedSection[i])-minTryteValue; j++ {
+			s.hash(segment, segment)
+		}
+	}
+
+	digest := make([]int8, hash.SizeTrits)
+	s.hash(digest, buf)
+
+	return digest
+}
+
+// Address computes an address from the concatenated digests of all key fragments.
+func Address(s Sponge, digests []int8) []int8 {
+	address := make([]int8, hash.SizeTrits)
+	s.hash(address, digests)
+	return address
+}
+
+// ValidateSignatures checks if the signature fragments of an input sign bundleHash with the private key of address.
+// The number of fragments equals the security level of the address.
+func ValidateSignatures(s Sponge, address []int8, fragments [][]int8, bundleHash []int8) bool {
+	if len(fragments) == 0 || len(fragments) > MaxSecurityLevel {
+		return false
+	}
+
+	normalized := NormalizeBundleHash(bundleHash)
+	digests := make([]int8, len(fragments)*hash.SizeTrits)
+
+	for i, fragment := range fragments {
+		if len(fragment) != FragmentTrits {
+			return false
+		}
+		section := normalized[i*normalizedSection : (i+1)*normalizedSection]
+		copy(digests[i*hash.SizeTrits:], Digest(s, section, fragment))
+	}
+
+	return trinary.Equals(Address(s, digests), address)
+}
@@ -0,0 +1,39 @@
+package signing
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/eaigner/igi/trinary"
+)
+
+func trits(t *testing.T, s string) []int8 {
+	buf := make([]int8, trinary.LenTritsFromTrytes(len(s)))
+	if _, err := trinary.TritsFromTrytes(buf, s); err != nil {
+		t.Fatal(err)
+	}
+	return buf
+}
+
+func TestNormalizeBundleHash(t *testing.T) {
+	type test struct {
+		in     string
+		expect []int8
+	}
+	table := []test{
+		{
+			"VAJOHANFEOTRSIPCLG9MIPENDFPLQQUGSBLBHMKZ9XVCUSWIKJOOHSPWJAXVLPTAKMPURYAYD9ONODVOW",
+			[]int8{8, 1, 10, -12, 8, 1, -13, 6, 5, -12, -7, -9, -8, 9, -11, 3, 12, 7, 0, 13, 9, -11, 5, -13, 4, 6, -11, -3, -10, -10, -6, 7, -8, 2, 12, 2, 8, 13, 11, -1, 0, -3, -5, 3, -6, -8, -4, 9, 11, 10, -12, -12, 8, -8, 13, 13, 13, 13, 13, -5, 12, -11, -7, 1, 11, 13, -11, -6, -9, -2, 1, -2, 4, 0, -12, -13, -12, 4, -5, -12, -4},
+		},
+		{
+			"LMHGSJVOFJGDCZKVR9AJBYVKCCLGFKOGLUXOFYQYHIVXGUPIDHSXGXR9IFJRLNRBYKGYFIBYUZ9WVHRTX",
+			[]int8{-13, -13, -13, -7, -8, 10, -5, -12, 6, 10, 7, 4, 3, -1, 11, -5, -9, 0, 1, 10, 2, -2, -5, 11, 3, 3, 12, -8, 6, 11, -12, 7, 12, -6, -3, -12, 6, -2, -10, -2, 8, 9, -5, -3, 7, -6, -11, 9, 4, 8, -8, -3, 7, -3, -10, 0, 9, 6, 10, -9, 12, -13, -9, 2, -2, 11, 7, -2, 6, 9, 2, -2, -6, -1, 0, -4, -5, 8, -9, -7, -3},
+		},
+	}
+
+	for _, v := range table {
+		if n := NormalizeBundleHash(trits(t, v.in)); !reflect.DeepEqual(n, v.expect) {
+			t.Fatal(n)
+		}
+	}
+}
@@ -1,14 +1,25 @@
 package storage
 
 import (
+	"encoding/binary"
+	"io"
+	"time"
+
 	"github.com/coreos/bbolt"
 )
 
-func NewBoltStore(path string) (Store, error) {
-	db, err := bolt.Open(path, 0600, nil)
+// NewBoltStore opens the bolt database at path and upgrades it to the current schema version.
+// Returns an error if the database has a newer schema version or belongs to another network.
+func NewBoltStore(path string, network Network) (Store, error) {
+	// Fail instead of blocking forever if another process holds the database lock
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
 	if err != nil {
 		return nil, err
 	}
+	if err := migrate(db, network); err != nil {
+		db.Close()
+		return nil, err
+	}
 	return &boltStore{db}, nil
 }
 
@@ -42,6 +53,65 @@ func (bs *boltStore) ReadBatch(batch []*Entry) error {
 	})
 }
 
+func (bs *boltStore) ForEach(b Bucket, fn func(key, value []byte) error) error {
+	return bs.db.View(func(tx *bolt.Tx) error {
+		if bucket := tx.Bucket(bucketKeys[b]); bucket != nil {
+			return bucket.ForEach(fn)
+		}
+		return nil
+	})
+}
+
+func (bs *boltStore) Backup(w io.Writer) (int64, error) {
+	var n int64
+	err := bs.db.View(func(tx *bolt.Tx) error {
+		var err error
+		n, err = tx.WriteTo(w)
+		return err
+	})
+	return n, err
+}
+
+func (bs *boltStore) Stats() (*Stats, error) {
+	dbStats := bs.db.Stats()
+	stats := &Stats{
+		FreePages: dbStats.FreePageN,
+		FreeBytes: dbStats.FreeAlloc,
+		Buckets:   make(map[string]BucketStats, len(allBuckets)),
+	}
+
+	err := bs.db.View(func(tx *bolt.Tx) error {
+		stats.Size = tx.Size()
+
+		if meta := tx.Bucket(bucketKeys[MetaBucket]); meta != nil {
+			if v := meta.Get(metaVersionKey); len(v) == 4 {
+				stats.SchemaVersion = int(binary.BigEndian.Uint32(v))
+			}
+			stats.Network = Network(meta.Get(metaNetworkKey))
+		}
+
+		for _, b := range allBuckets {
+			bucket := tx.Bucket(bucketKeys[b])
+			if bucket == nil {
+				continue
+			}
+			s := bucket.Stats()
+			stats.Buckets[b.String()] = BucketStats{
+				Keys:       s.KeyN,
+				Depth:      s.Depth,
+				Bytes:      s.BranchInuse + s.LeafInuse + s.InlineBucketInuse,
+				AllocBytes: s.BranchAlloc + s.LeafAlloc,
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
 func (bs *boltStore) Close() error {
 	return bs.db.Close()
 }
@@ -10,7 +10,7 @@ func TestExists(t *testing.T) {
 
 	os.Remove(dbPath)
 
-	s, err := NewBoltStore(dbPath)
+	s, err := NewBoltStore(dbPath, Mainnet)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -42,3 +42,131 @@ func TestExists(t *testing.T) {
 		t.Fatal("should exist")
 	}
 }
+
+func TestForEach(t *testing.T) {
+	dbPath := "test_foreach.db"
+
+	os.Remove(dbPath)
+
+	s, err := NewBoltStore(dbPath, Mainnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	defer os.Remove(dbPath)
+
+	err = s.WriteBatch([]Entry{
+		{Bucket: TransactionBucket, Key: []byte("a"), Value: []byte("1")},
+		{Bucket: TransactionBucket, Key: []byte("b"), Value: []byte("2")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var keys, values string
+
+	err = s.ForEach(TransactionBucket, func(k, v []byte) error {
+		keys += string(k)
+		values += string(v)
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keys != "ab" || values != "12" {
+		t.Fatal(keys, values)
+	}
+}
+
+func TestBackup(t *testing.T) {
+	dbPath := "test_backup.db"
+	backupPath := "test_backup_copy.db"
+
+	os.Remove(dbPath)
+	os.Remove(backupPath)
+
+	s, err := NewBoltStore(dbPath, Mainnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	defer os.Remove(dbPath)
+
+	k := []byte("testKey")
+	v := []byte("testValue")
+
+	if err := Write(s, k, v, TransactionBucket); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Create(backupPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(backupPath)
+
+	n, err := s.Backup(f)
+	f.Close()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n == 0 {
+		t.Fatal(n)
+	}
+
+	b, err := NewBoltStore(backupPath, Mainnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	value, err := Read(b, k, TransactionBucket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(value) != string(v) {
+		t.Fatal(value)
+	}
+}
+
+func TestStats(t *testing.T) {
+	dbPath := "test_stats.db"
+
+	os.Remove(dbPath)
+
+	s, err := NewBoltStore(dbPath, Testnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	defer os.Remove(dbPath)
+
+	if err := Write(s, []byte("testKey"), []byte("testValue"), TransactionBucket); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := s.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.SchemaVersion != SchemaVersion {
+		t.Fatal(stats.SchemaVersion)
+	}
+	if stats.Network != Testnet {
+		t.Fatal(stats.Network)
+	}
+	if stats.Size == 0 {
+		t.Fatal(stats.Size)
+	}
+
+	b, ok := stats.Buckets[TransactionBucket.String()]
+
+	if !ok {
+		t.Fatal(stats.Buckets)
+	}
+	if b.Keys != 1 || b.Depth != 1 || b.Bytes == 0 {
+		t.Fatal(b)
+	}
+}
@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/coreos/bbolt"
+
+	"github.com/eaigner/igi/hash"
+	"github.com/eaigner/igi/trinary"
+)
+
+// SchemaVersion is the database layout version written by this build.
+const SchemaVersion = 1
+
+// Network identifies the network a database belongs to.
+type Network string
+
+const (
+	Mainnet Network = "mainnet"
+	Testnet Network = "testnet"
+)
+
+var (
+	metaVersionKey = []byte("version")
+	metaNetworkKey = []byte("network")
+)
+
+// compactBatchSize limits the number of keys a migration rewrites per write transaction,
+// so large databases are not rewritten in a single transaction.
+var compactBatchSize = 10000
+
+// migrations[i] upgrades a database from schema version i to i+1.
+// Databases created before versioning was introduced have version 0.
+// Migrations may commit in several transactions and must be safe to run again if they were interrupted,
+// since the schema version is only updated after a migration completed.
+var migrations = []func(db *bolt.DB) error{
+	compactTransactionKeys, // 0 -> 1
+}
+
+// migrate checks the schema version and network recorded in the meta bucket and runs all pending migrations.
+// Databases with a newer schema version or from another network are rejected.
+func migrate(db *bolt.DB, network Network) error {
+	version := 0
+
+	err := db.Update(func(tx *bolt.Tx) error {
+		meta, err := tx.CreateBucketIfNotExists(bucketKeys[MetaBucket])
+		if err != nil {
+			return err
+		}
+		if v := meta.Get(metaVersionKey); len(v) == 4 {
+			version = int(binary.BigEndian.Uint32(v))
+		}
+		if version > SchemaVersion {
+			return fmt.Errorf("database schema version %d is newer than supported version %d", version, SchemaVersion)
+		}
+		if v := meta.Get(metaNetworkKey); v != nil && Network(v) != network {
+			return fmt.Errorf("database belongs to %s, not %s", v, network)
+		}
+		return meta.Put(metaNetworkKey, []byte(network))
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for ; version < SchemaVersion; version++ {
+		if err := migrations[version](db); err != nil {
+			return fmt.Errorf("migrating database schema from version %d: %v", version, err)
+		}
+		if err := writeVersion(db, version+1); err != nil {
+			return err
+		}
+	}
+
+	// New databases start at the current version
+	return writeVersion(db, version)
+}
+
+func writeVersion(db *bolt.DB, version int) error {
+	var v [4]byte
+	binary.BigEndian.PutUint32(v[:], uint32(version))
+
+	return db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketKeys[MetaBucket]).Put(metaVersionKey, v[:])
+	})
+}
+
+// compactTransactionKeys rewrites transaction keys that were stored with one byte per trit
+// (hash.SizeTrits bytes) to the packed hash.SizeBytes encoding, compactBatchSize keys per transaction.
+// Legacy keys containing bytes that are not valid trits are left untouched.
+func compactTransactionKeys(db *bolt.DB) error {
+	var next []byte // first key of the next batch, nil to start at the first key
+
+	for {
+		done := false
+
+		err := db.Update(func(tx *bolt.Tx) error {
+			bucket := tx.Bucket(bucketKeys[TransactionBucket])
+			if bucket == nil {
+				done = true
+				return nil
+			}
+
+			// Bolt does not allow modifying a bucket while iterating it, so collect the keys first.
+			var keys [][]byte
+
+			c := bucket.Cursor()
+			k, _ := c.First()
+			if next != nil {
+				k, _ = c.Seek(next)
+			}
+			for ; k != nil && len(keys) < compactBatchSize; k, _ = c.Next() {
+				if len(k) == hash.SizeTrits && trinary.Validate(hash.ToInt8(k)) {
+					keys = append(keys, append([]byte(nil), k...))
+				}
+			}
+
+			// Keys before k are not modified, so the next batch continues at k
+			if k == nil {
+				done = true
+			} else {
+				next = append([]byte(nil), k...)
+			}
+
+			for _, k := range keys {
+				v := append([]byte(nil), bucket.Get(k)...)
+				if err := bucket.Put(hash.Pack(hash.ToInt8(k)), v); err != nil {
+					return err
+				}
+				if err := bucket.Delete(k); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+
+		if err != nil || done {
+			return err
+		}
+	}
+}
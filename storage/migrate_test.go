@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"encoding/binary"
+	"os"
+	"testing"
+
+	"github.com/coreos/bbolt"
+
+	"github.com/eaigner/igi/hash"
+)
+
+func TestCompactTransactionKeys(t *testing.T) {
+	dbPath := "test_compact.db"
+
+	os.Remove(dbPath)
+	defer os.Remove(dbPath)
+
+	// Rewrite the keys in several transactions
+	defer func(n int) { compactBatchSize = n }(compactBatchSize)
+	compactBatchSize = 2
+
+	s, err := NewBoltStore(dbPath, Mainnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var hashes [][]int8
+	for i := 0; i < 5; i++ {
+		h := make([]int8, hash.SizeTrits)
+		h[0] = 1
+		h[1] = int8(i%3) - 1
+		h[2] = int8(i/3) - 1
+		hashes = append(hashes, h)
+	}
+	v := []byte("testValue")
+
+	for _, h := range hashes {
+		if err := Write(s, hash.ToBytes(h), v, TransactionBucket); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A legacy key with invalid trits is left untouched
+	malformed := make([]byte, hash.SizeTrits)
+	malformed[0] = 5
+
+	if err := Write(s, malformed, v, TransactionBucket); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a database created before schema versioning
+	err = s.(*boltStore).db.Update(func(tx *bolt.Tx) error {
+		return tx.DeleteBucket(bucketKeys[MetaBucket])
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	// Reopen to run the migration
+	s, err = NewBoltStore(dbPath, Mainnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	for i, h := range hashes {
+		exists, err := Exists(s, hash.ToBytes(h), TransactionBucket)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exists {
+			t.Fatal(i, "legacy key should be removed")
+		}
+
+		b, err := Read(s, hash.Pack(h), TransactionBucket)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != string(v) {
+			t.Fatal(i, b)
+		}
+	}
+
+	if exists, err := Exists(s, malformed, TransactionBucket); err != nil || !exists {
+		t.Fatal(exists, err)
+	}
+
+	version, err := Read(s, metaVersionKey, MetaBucket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if binary.BigEndian.Uint32(version) != SchemaVersion {
+		t.Fatal(version)
+	}
+}
+
+func TestMigrateNetworkMismatch(t *testing.T) {
+	dbPath := "test_network.db"
+
+	os.Remove(dbPath)
+	defer os.Remove(dbPath)
+
+	s, err := NewBoltStore(dbPath, Testnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	s, err = NewBoltStore(dbPath, Mainnet)
+	if err == nil {
+		s.Close()
+		t.Fatal("should refuse testnet database")
+	}
+
+	s, err = NewBoltStore(dbPath, Testnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+}
+
+func TestMigrateNewerVersion(t *testing.T) {
+	dbPath := "test_version.db"
+
+	os.Remove(dbPath)
+	defer os.Remove(dbPath)
+
+	s, err := NewBoltStore(dbPath, Mainnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var v [4]byte
+	binary.BigEndian.PutUint32(v[:], SchemaVersion+1)
+
+	err = s.(*boltStore).db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketKeys[MetaBucket]).Put(metaVersionKey, v[:])
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	s, err = NewBoltStore(dbPath, Mainnet)
+	if err == nil {
+		s.Close()
+		t.Fatal("should refuse newer schema version")
+	}
+}
@@ -1,11 +1,23 @@
 package storage
 
+import "io"
+
 const (
 	TransactionBucket Bucket = 1
+	MetaBucket        Bucket = 2
+	MilestoneBucket   Bucket = 3
 )
 
 var allBuckets = []Bucket{
 	TransactionBucket,
+	MetaBucket,
+	MilestoneBucket,
+}
+
+var bucketNames = map[Bucket]string{
+	TransactionBucket: "transaction",
+	MetaBucket:        "meta",
+	MilestoneBucket:   "milestone",
 }
 
 var bucketKeys = map[Bucket][]byte{}
@@ -19,6 +31,10 @@ func init() {
 
 type Bucket byte
 
+func (b Bucket) String() string {
+	return bucketNames[b]
+}
+
 type Entry struct {
 	Bucket Bucket
 	Key    []byte
@@ -29,6 +45,24 @@ func (e *Entry) BucketKey() []byte {
 	return bucketKeys[e.Bucket]
 }
 
+// Stats describes the size and layout of a database.
+type Stats struct {
+	SchemaVersion int                    `json:"schemaVersion"`
+	Network       Network                `json:"network"`
+	Size          int64                  `json:"size"`      // database size in bytes
+	FreePages     int                    `json:"freePages"` // pages on the freelist
+	FreeBytes     int                    `json:"freeBytes"` // bytes allocated in free pages
+	Buckets       map[string]BucketStats `json:"buckets"`   // keyed by bucket name
+}
+
+// BucketStats describes a single bucket.
+type BucketStats struct {
+	Keys       int `json:"keys"`
+	Depth      int `json:"depth"`      // levels in the B+tree
+	Bytes      int `json:"bytes"`      // bytes used by keys, values and page headers, including inlined buckets
+	AllocBytes int `json:"allocBytes"` // bytes allocated for the bucket's pages
+}
+
 type Store interface {
 	// WriteBatch writes a batch of entries to the DB.
 	WriteBatch(batch []Entry) error
@@ -36,6 +70,18 @@ type Store interface {
 	// ReadBatch reads a batch of entries from the DB. Upon success, the bytes value for each entry should be set.
 	ReadBatch(batch []*Entry) error
 
+	// ForEach calls fn for every key/value pair in bucket. The slices are only valid until fn returns.
+	// Iteration stops at the first error returned by fn.
+	ForEach(bucket Bucket, fn func(key, value []byte) error) error
+
+	// Backup writes a consistent snapshot of the database to w.
+	// The snapshot is read in a single read transaction, writers may stall while it is open.
+	// Returns the number of bytes written.
+	Backup(w io.Writer) (int64, error)
+
+	// Stats returns size and layout statistics of the database.
+	Stats() (*Stats, error)
+
 	// Close closes the store
 	Close() error
 }
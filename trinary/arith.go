@@ -0,0 +1,74 @@
+package trinary
+
+// Add sets dst to a + b and returns the carry out of the most significant trit, which is non-zero on overflow.
+// Operands shorter than dst are extended with zeros, longer ones are truncated to len(dst).
+// dst may alias a or b.
+func Add(dst, a, b []int8) int8 {
+	var carry int8
+
+	for i := range dst {
+		sum := carry
+		if i < len(a) {
+			sum += a[i]
+		}
+		if i < len(b) {
+			sum += b[i]
+		}
+		carry = 0
+		switch {
+		case sum > maxTritValue:
+			sum -= tritRadix
+			carry = 1
+		case sum < minTritValue:
+			sum += tritRadix
+			carry = -1
+		}
+		dst[i] = sum
+	}
+
+	return carry
+}
+
+// Increment adds 1 to t, wrapping around on overflow. Returns the number of trits that changed.
+func Increment(t []int8) int {
+	for i := range t {
+		if t[i] < maxTritValue {
+			t[i]++
+			return i + 1
+		}
+		t[i] = minTritValue
+	}
+	return len(t)
+}
+
+// Negate negates t in place.
+func Negate(t []int8) {
+	for i, v := range t {
+		t[i] = -v
+	}
+}
+
+// Compare compares the balanced ternary numbers a and b and returns -1 if a < b, 0 if a == b and 1 if a > b.
+// The operands may have different lengths.
+func Compare(a, b []int8) int {
+	n := len(a)
+	if len(b) > n {
+		n = len(b)
+	}
+	for i := n - 1; i >= 0; i-- {
+		var x, y int8
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
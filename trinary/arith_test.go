@@ -0,0 +1,118 @@
+package trinary
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestAdd(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 1000; i++ {
+		x, y := r.Int63n(1<<40)-1<<39, r.Int63n(1<<40)-1<<39
+		a, b := make([]int8, 27), make([]int8, 27)
+		FromInt64(a, x)
+		FromInt64(b, y)
+
+		sum := make([]int8, 27)
+		if c := Add(sum, a, b); c != 0 {
+			t.Fatal(x, y, c)
+		}
+		if v := Int64(sum); v != x+y {
+			t.Fatal(x, y, v)
+		}
+
+		// In place with a shorter operand
+		c := make([]int8, 10)
+		FromInt64(c, 1000)
+		Add(a, a, c)
+		if v := Int64(a); v != x+1000 {
+			t.Fatal(x, v)
+		}
+	}
+}
+
+func TestAddCarry(t *testing.T) {
+	type test struct {
+		a, b  int64
+		sum   int64
+		carry int8
+	}
+	table := []test{
+		{13, 1, -13, 1}, // 3 trits hold [-13, 13]
+		{-13, -1, 13, -1},
+		{13, -13, 0, 0},
+		{7, 6, 13, 0},
+	}
+
+	for _, v := range table {
+		a, b, sum := make([]int8, 3), make([]int8, 3), make([]int8, 3)
+		FromInt64(a, v.a)
+		FromInt64(b, v.b)
+
+		if c := Add(sum, a, b); c != v.carry {
+			t.Fatal(v, c)
+		}
+		if x := Int64(sum); x != v.sum {
+			t.Fatal(v, x)
+		}
+	}
+}
+
+func TestIncrement(t *testing.T) {
+	a := make([]int8, 4)
+	FromInt64(a, -40)
+
+	for v := int64(-40); v < 40; v++ {
+		before := append([]int8{}, a...)
+		n := Increment(a)
+		if x := Int64(a); x != v+1 {
+			t.Fatal(v, x)
+		}
+		for i := range a {
+			if changed := a[i] != before[i]; changed != (i < n) {
+				t.Fatal(v, n, before, a)
+			}
+		}
+	}
+
+	// Wraps around
+	if n := Increment(a); n != len(a) || Int64(a) != -40 {
+		t.Fatal(n, a)
+	}
+}
+
+func TestNegate(t *testing.T) {
+	a := make([]int8, 27)
+	FromInt64(a, 123456789)
+	Negate(a)
+
+	if v := Int64(a); v != -123456789 {
+		t.Fatal(v)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 1000; i++ {
+		x, y := r.Int63n(2000)-1000, r.Int63n(2000)-1000
+		if i%10 == 0 {
+			y = x
+		}
+		a, b := make([]int8, 7), make([]int8, 7+i%3)
+		FromInt64(a, x)
+		FromInt64(b, y)
+
+		expect := 0
+		switch {
+		case x < y:
+			expect = -1
+		case x > y:
+			expect = 1
+		}
+		if c := Compare(a, b); c != expect {
+			t.Fatal(x, y, c)
+		}
+	}
+}
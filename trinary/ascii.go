@@ -0,0 +1,95 @@
+package trinary
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	SignatureMessageFragmentTrytes = 2187
+	TagTrytes                      = 27
+	trytesPerByte                  = 2
+	paddingTryte                   = '9'
+)
+
+var (
+	errOddTrytes = errors.New("bytes are encoded as an even number of trytes")
+	errTooLong   = errors.New("trytes exceed the padded length")
+)
+
+// BytesToTrytes encodes every byte of b as two trytes: the tryte for b % 27 followed by the tryte for b / 27.
+func BytesToTrytes(b []byte) string {
+	buf := make([]byte, len(b)*trytesPerByte)
+	for i, v := range b {
+		buf[i*trytesPerByte] = tryteAlphabet[v%27]
+		buf[i*trytesPerByte+1] = tryteAlphabet[v/27]
+	}
+	return string(buf)
+}
+
+// TrytesToBytes decodes trytes encoded with BytesToTrytes.
+func TrytesToBytes(s string) ([]byte, error) {
+	if len(s)%trytesPerByte != 0 {
+		return nil, errOddTrytes
+	}
+	b := make([]byte, len(s)/trytesPerByte)
+	for i := range b {
+		lo, ok := tryteRuneIndex[rune(s[i*trytesPerByte])]
+		if !ok {
+			return nil, invalidTryteError(rune(s[i*trytesPerByte]), i*trytesPerByte)
+		}
+		hi, ok := tryteRuneIndex[rune(s[i*trytesPerByte+1])]
+		if !ok {
+			return nil, invalidTryteError(rune(s[i*trytesPerByte+1]), i*trytesPerByte+1)
+		}
+		v := lo + hi*27
+		if v > 255 {
+			return nil, fmt.Errorf("trytes %q at position %d do not encode a byte", s[i*trytesPerByte:(i+1)*trytesPerByte], i*trytesPerByte)
+		}
+		b[i] = byte(v)
+	}
+	return b, nil
+}
+
+// StringToTrytes encodes the UTF-8 bytes of s as trytes.
+func StringToTrytes(s string) string {
+	return BytesToTrytes([]byte(s))
+}
+
+// TrytesToString decodes trytes encoded with StringToTrytes.
+func TrytesToString(s string) (string, error) {
+	b, err := TrytesToBytes(s)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// Pad appends 9s to s up to n trytes. Returns an error if s is longer than n trytes.
+func Pad(s string, n int) (string, error) {
+	if len(s) > n {
+		return "", errTooLong
+	}
+	return s + strings.Repeat(string(paddingTryte), n-len(s)), nil
+}
+
+// PadSignatureMessageFragment pads s to the size of a signature message fragment.
+func PadSignatureMessageFragment(s string) (string, error) {
+	return Pad(s, SignatureMessageFragmentTrytes)
+}
+
+// PadTag pads s to the size of a tag.
+func PadTag(s string) (string, error) {
+	return Pad(s, TagTrytes)
+}
+
+// TrimPadding removes trailing 9s, keeping an even number of trytes so the result can be decoded
+// with TrytesToBytes. Trailing zero bytes are removed as well, since they are encoded as 99.
+func TrimPadding(s string) string {
+	s = strings.TrimRight(s, string(paddingTryte))
+	if len(s)%trytesPerByte != 0 {
+		s += string(paddingTryte)
+	}
+	return s
+}
@@ -0,0 +1,110 @@
+package trinary
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStringToTrytes(t *testing.T) {
+	type test struct {
+		in     string
+		trytes string
+	}
+	table := []test{
+		{"", ""},
+		{"IOTA", "SBYBCCKB"},
+		{"Hello, World!", "RBTC9D9DCDQAEAFCCDFD9DSCFA"},
+		{`{"key":"value","n":1}`, "ODGAZCTCMDGADBGAJDPC9DIDTCGAQAGABDGADBVAQD"},
+	}
+
+	for _, v := range table {
+		if s := StringToTrytes(v.in); s != v.trytes {
+			t.Fatal(v.in, s)
+		}
+		s, err := TrytesToString(v.trytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != v.in {
+			t.Fatal(s)
+		}
+	}
+}
+
+func TestBytesToTrytes(t *testing.T) {
+	b := make([]byte, 256)
+	for i := range b {
+		b[i] = byte(i)
+	}
+
+	s := BytesToTrytes(b)
+
+	if len(s) != 512 || !ValidTrytes(s) {
+		t.Fatal(s)
+	}
+
+	out, err := TrytesToBytes(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != string(b) {
+		t.Fatal(out)
+	}
+
+	// UTF-8 round trip
+	if s, err := TrytesToString(StringToTrytes("größer → 😀")); err != nil || s != "größer → 😀" {
+		t.Fatal(s, err)
+	}
+}
+
+func TestTrytesToBytesInvalid(t *testing.T) {
+	type test struct {
+		in  string
+		err string
+	}
+	table := []test{
+		{"ABC", errOddTrytes.Error()},
+		{"AB9a", `invalid tryte 'a' at position 3`},
+		{"9Z", `trytes "9Z" at position 0 do not encode a byte`},
+	}
+
+	for _, v := range table {
+		if _, err := TrytesToBytes(v.in); err == nil || err.Error() != v.err {
+			t.Fatal(v.in, err)
+		}
+	}
+}
+
+func TestPad(t *testing.T) {
+	msg := StringToTrytes(`{"key":"value"}`)
+
+	s, err := PadSignatureMessageFragment(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s) != SignatureMessageFragmentTrytes || !strings.HasPrefix(s, msg) {
+		t.Fatal(s)
+	}
+	if out, err := TrytesToString(TrimPadding(s)); err != nil || out != `{"key":"value"}` {
+		t.Fatal(out, err)
+	}
+
+	tag, err := PadTag("IGI")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag != "IGI999999999999999999999999" {
+		t.Fatal(tag)
+	}
+	if _, err := PadTag(strings.Repeat("A", TagTrytes+1)); err != errTooLong {
+		t.Fatal(err)
+	}
+
+	// An odd number of trytes after trimming is padded to a full byte
+	if s := TrimPadding("IC999"); s != "IC" {
+		t.Fatal(s)
+	}
+	if s := TrimPadding("I9999"); s != "I9" {
+		t.Fatal(s)
+	}
+}
@@ -0,0 +1,185 @@
+package trinary
+
+import "errors"
+
+const (
+	tritsPerT4B1Byte = 4
+	maxT4B1Value     = 40 // (3^4 - 1) / 2
+	kerlTrits        = 243
+	kerlBytes        = 48
+)
+
+var (
+	errInvalidTrit       = errors.New("invalid trit")
+	errInvalidByte       = errors.New("byte is not a valid packed trit value")
+	errInvalidKerlLength = errors.New("length must be a multiple of 243 trits or 48 bytes")
+)
+
+// Codec packs trits into bytes and unpacks them again.
+type Codec interface {
+	// EncodedLen returns the number of bytes n trits are packed into.
+	EncodedLen(n int) int
+
+	// DecodedLen returns the number of trits n bytes are unpacked into.
+	DecodedLen(n int) int
+
+	// Encode packs src into dst, which must be at least EncodedLen(len(src)) long.
+	// Returns the number of bytes written.
+	Encode(dst []byte, src []int8) (int, error)
+
+	// Decode unpacks src into dst, which must be at least DecodedLen(len(src)) long.
+	// Returns the number of trits written.
+	Decode(dst []int8, src []byte) (int, error)
+}
+
+var (
+	// T5B1 packs 5 trits into a byte, used for gossip and storage keys. Same as Bytes and Trits.
+	T5B1 Codec = t5b1{}
+
+	// T1B1 stores every trit as an int8 byte.
+	T1B1 Codec = t1b1{}
+
+	// T4B1 packs 4 trits into a byte.
+	T4B1 Codec = t4b1{}
+
+	// T243B48 converts chunks of 243 trits to 48 byte big-endian two's complement integers,
+	// with the last trit of every chunk treated as zero. This is the binary layout Kerl hashes.
+	T243B48 Codec = t243b48{}
+)
+
+type t5b1 struct{}
+
+func (t5b1) EncodedLen(n int) int {
+	return LenBytes(n)
+}
+
+func (t5b1) DecodedLen(n int) int {
+	return LenTrits(n)
+}
+
+func (t5b1) Encode(dst []byte, src []int8) (int, error) {
+	return Bytes(dst, src)
+}
+
+func (t5b1) Decode(dst []int8, src []byte) (int, error) {
+	return Trits(dst, src)
+}
+
+type t1b1 struct{}
+
+func (t1b1) EncodedLen(n int) int {
+	return n
+}
+
+func (t1b1) DecodedLen(n int) int {
+	return n
+}
+
+func (t1b1) Encode(dst []byte, src []int8) (int, error) {
+	if len(dst) < len(src) {
+		return 0, errBufferTooSmall
+	}
+	for i, v := range src {
+		dst[i] = byte(v)
+	}
+	return len(src), nil
+}
+
+func (t1b1) Decode(dst []int8, src []byte) (int, error) {
+	if len(dst) < len(src) {
+		return 0, errBufferTooSmall
+	}
+	for i, v := range src {
+		if !validTrit(int8(v)) {
+			return 0, errInvalidTrit
+		}
+		dst[i] = int8(v)
+	}
+	return len(src), nil
+}
+
+type t4b1 struct{}
+
+func (t4b1) EncodedLen(n int) int {
+	return (n + tritsPerT4B1Byte - 1) / tritsPerT4B1Byte
+}
+
+func (t4b1) DecodedLen(n int) int {
+	return n * tritsPerT4B1Byte
+}
+
+func (c t4b1) Encode(dst []byte, src []int8) (int, error) {
+	n := c.EncodedLen(len(src))
+	if len(dst) < n {
+		return 0, errBufferTooSmall
+	}
+	for i := 0; i < n; i++ {
+		chunk := src[i*tritsPerT4B1Byte:]
+		if len(chunk) > tritsPerT4B1Byte {
+			chunk = chunk[:tritsPerT4B1Byte]
+		}
+		var v int8
+		for j := len(chunk) - 1; j >= 0; j-- {
+			v = v*tritRadix + chunk[j]
+		}
+		dst[i] = byte(v)
+	}
+	return n, nil
+}
+
+func (c t4b1) Decode(dst []int8, src []byte) (int, error) {
+	n := c.DecodedLen(len(src))
+	if len(dst) < n {
+		return 0, errBufferTooSmall
+	}
+	for i, b := range src {
+		x := int(int8(b))
+		if x < -maxT4B1Value || x > maxT4B1Value {
+			return 0, errInvalidByte
+		}
+		if x < 0 {
+			x += len(bytesToTrits)
+		}
+		// The fifth trit of values in the t4b1 range is always zero
+		copy(dst[i*tritsPerT4B1Byte:], bytesToTrits[x][:tritsPerT4B1Byte])
+	}
+	return n, nil
+}
+
+type t243b48 struct{}
+
+func (t243b48) EncodedLen(n int) int {
+	return n / kerlTrits * kerlBytes
+}
+
+func (t243b48) DecodedLen(n int) int {
+	return n / kerlBytes * kerlTrits
+}
+
+func (c t243b48) Encode(dst []byte, src []int8) (int, error) {
+	if len(src)%kerlTrits != 0 {
+		return 0, errInvalidKerlLength
+	}
+	n := c.EncodedLen(len(src))
+	if len(dst) < n {
+		return 0, errBufferTooSmall
+	}
+	for i := 0; i < len(src)/kerlTrits; i++ {
+		tritsToBytes384(dst[i*kerlBytes:(i+1)*kerlBytes], src[i*kerlTrits:(i+1)*kerlTrits])
+	}
+	return n, nil
+}
+
+func (c t243b48) Decode(dst []int8, src []byte) (int, error) {
+	if len(src)%kerlBytes != 0 {
+		return 0, errInvalidKerlLength
+	}
+	n := c.DecodedLen(len(src))
+	if len(dst) < n {
+		return 0, errBufferTooSmall
+	}
+	for i := 0; i < len(src)/kerlBytes; i++ {
+		bytes384ToTrits(dst[i*kerlTrits:(i+1)*kerlTrits], src[i*kerlBytes:(i+1)*kerlBytes])
+	}
+	return n, nil
+}
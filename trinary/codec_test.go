@@ -0,0 +1,178 @@
+package trinary
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+var codecs = map[string]Codec{
+	"t5b1":    T5B1,
+	"t1b1":    T1B1,
+	"t4b1":    T4B1,
+	"t243b48": T243B48,
+}
+
+func randomTrits(r *rand.Rand, n int) []int8 {
+	t := make([]int8, n)
+	for i := range t {
+		t[i] = int8(r.Intn(3)) - 1
+	}
+	return t
+}
+
+func TestCodecRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for name, c := range codecs {
+		for _, n := range []int{0, 243, 486, 8019} {
+			src := randomTrits(r, n)
+			if c == T243B48 {
+				// The last trit of every chunk is not encoded
+				for i := 242; i < n; i += 243 {
+					src[i] = 0
+				}
+			}
+
+			b := make([]byte, c.EncodedLen(n))
+			if x, err := c.Encode(b, src); err != nil || x != len(b) {
+				t.Fatal(name, n, x, err)
+			}
+
+			dst := make([]int8, c.DecodedLen(len(b)))
+			x, err := c.Decode(dst, b)
+			if err != nil || x != len(dst) {
+				t.Fatal(name, n, x, err)
+			}
+			if len(dst) < n || !Equals(dst[:n], src) {
+				t.Fatal(name, n)
+			}
+			for _, v := range dst[n:] {
+				if v != 0 {
+					t.Fatal(name, n, dst[n:])
+				}
+			}
+		}
+	}
+}
+
+func TestCodecLen(t *testing.T) {
+	type test struct {
+		c        Codec
+		trits    int
+		bytes    int
+		decoded  int
+		overflow int // trits that are padded
+	}
+	table := []test{
+		{T5B1, 8019, 1604, 8020, 1},
+		{T1B1, 8019, 8019, 8019, 0},
+		{T4B1, 8019, 2005, 8020, 1},
+		{T243B48, 8019, 1584, 8019, 0},
+	}
+
+	for _, v := range table {
+		if n := v.c.EncodedLen(v.trits); n != v.bytes {
+			t.Fatal(v.c, n)
+		}
+		if n := v.c.DecodedLen(v.bytes); n != v.decoded || n-v.trits != v.overflow {
+			t.Fatal(v.c, n)
+		}
+	}
+}
+
+func TestT243B48(t *testing.T) {
+	type test struct {
+		trits []int8
+		bytes []byte
+	}
+	table := []test{
+		{[]int8{}, make([]byte, 48)},
+		{[]int8{1}, append(make([]byte, 47), 1)},
+		{[]int8{0, 1}, append(make([]byte, 47), 3)},
+		{[]int8{-1}, bytes.Repeat([]byte{0xff}, 48)},
+		{[]int8{-1, -1}, append(bytes.Repeat([]byte{0xff}, 47), 0xfc)}, // -4
+	}
+
+	for _, v := range table {
+		src := make([]int8, 243)
+		copy(src, v.trits)
+		src[242] = 1 // ignored
+
+		b := make([]byte, 48)
+		if _, err := T243B48.Encode(b, src); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(b, v.bytes) {
+			t.Fatal(v.trits, b)
+		}
+
+		dst := make([]int8, 243)
+		if _, err := T243B48.Decode(dst, b); err != nil {
+			t.Fatal(err)
+		}
+		src[242] = 0
+		if !Equals(dst, src) {
+			t.Fatal(v.trits, dst)
+		}
+	}
+}
+
+func TestCodecInvalid(t *testing.T) {
+	if _, err := T1B1.Decode(make([]int8, 2), []byte{1, 2}); err != errInvalidTrit {
+		t.Fatal(err)
+	}
+	if _, err := T4B1.Decode(make([]int8, 4), []byte{41}); err != errInvalidByte {
+		t.Fatal(err)
+	}
+	if _, err := T4B1.Decode(make([]int8, 4), []byte{byte(256 - 41)}); err != errInvalidByte {
+		t.Fatal(err)
+	}
+	if _, err := T243B48.Encode(make([]byte, 48), make([]int8, 242)); err != errInvalidKerlLength {
+		t.Fatal(err)
+	}
+	if _, err := T243B48.Decode(make([]int8, 243), make([]byte, 47)); err != errInvalidKerlLength {
+		t.Fatal(err)
+	}
+	for name, c := range codecs {
+		if _, err := c.Encode(nil, make([]int8, 243)); err != errBufferTooSmall {
+			t.Fatal(name, err)
+		}
+		if _, err := c.Decode(nil, make([]byte, 48)); err != errBufferTooSmall {
+			t.Fatal(name, err)
+		}
+	}
+}
+
+func benchmarkEncode(b *testing.B, c Codec) {
+	src := randomTrits(rand.New(rand.NewSource(1)), 8019)
+	dst := make([]byte, c.EncodedLen(len(src)))
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		c.Encode(dst, src)
+	}
+}
+
+func benchmarkDecode(b *testing.B, c Codec) {
+	src := randomTrits(rand.New(rand.NewSource(1)), 8019)
+	buf := make([]byte, c.EncodedLen(len(src)))
+	c.Encode(buf, src)
+	dst := make([]int8, c.DecodedLen(len(buf)))
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		c.Decode(dst, buf)
+	}
+}
+
+func BenchmarkEncodeT5B1(b *testing.B)    { benchmarkEncode(b, T5B1) }
+func BenchmarkEncodeT1B1(b *testing.B)    { benchmarkEncode(b, T1B1) }
+func BenchmarkEncodeT4B1(b *testing.B)    { benchmarkEncode(b, T4B1) }
+func BenchmarkEncodeT243B48(b *testing.B) { benchmarkEncode(b, T243B48) }
+func BenchmarkDecodeT5B1(b *testing.B)    { benchmarkDecode(b, T5B1) }
+func BenchmarkDecodeT1B1(b *testing.B)    { benchmarkDecode(b, T1B1) }
+func BenchmarkDecodeT4B1(b *testing.B)    { benchmarkDecode(b, T4B1) }
+func BenchmarkDecodeT243B48(b *testing.B) { benchmarkDecode(b, T243B48) }
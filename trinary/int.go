@@ -0,0 +1,67 @@
+package trinary
+
+import (
+	"errors"
+	"math/big"
+)
+
+var (
+	errOverflow = errors.New("value does not fit into trits")
+)
+
+// FromInt64 writes v as balanced ternary number to dst, least significant trit first.
+// Unused trits are set to zero. Returns an error if v does not fit into len(dst) trits.
+func FromInt64(dst []int8, v int64) error {
+	for i := range dst {
+		q, r := v/int64(tritRadix), int8(v%int64(tritRadix))
+		switch {
+		case r > maxTritValue:
+			r -= tritRadix
+			q++
+		case r < minTritValue:
+			r += tritRadix
+			q--
+		}
+		dst[i] = r
+		v = q
+	}
+	if v != 0 {
+		return errOverflow
+	}
+	return nil
+}
+
+// FromBigInt writes v as balanced ternary number to dst, least significant trit first.
+// Unused trits are set to zero. Returns an error if v does not fit into len(dst) trits.
+func FromBigInt(dst []int8, v *big.Int) error {
+	var (
+		q     = new(big.Int).Set(v)
+		m     = new(big.Int)
+		radix = big.NewInt(int64(tritRadix))
+	)
+	for i := range dst {
+		// Euclidean division, the remainder is in [0, 2]
+		q.DivMod(q, radix, m)
+		r := int8(m.Int64())
+		if r > maxTritValue {
+			r -= tritRadix
+			q.Add(q, big.NewInt(1))
+		}
+		dst[i] = r
+	}
+	if q.Sign() != 0 {
+		return errOverflow
+	}
+	return nil
+}
+
+// BigInt converts trits to a big.Int value.
+func BigInt(t []int8) *big.Int {
+	v := new(big.Int)
+	radix := big.NewInt(int64(tritRadix))
+	for i := len(t) - 1; i >= 0; i-- {
+		v.Mul(v, radix)
+		v.Add(v, big.NewInt(int64(t[i])))
+	}
+	return v
+}
@@ -0,0 +1,130 @@
+package trinary
+
+import "encoding/binary"
+
+// 384-bit integers are stored as little-endian 32 bit words. Decoding needs one more word,
+// since 243 unsigned trits exceed 2^384.
+const (
+	int384Words = kerlBytes / 4
+	int416Words = int384Words + 1
+)
+
+var (
+	// halfMax242 is (3^242 - 1) / 2, the largest value of 242 balanced trits.
+	halfMax242 [int384Words]uint32
+	// halfMax243 is (3^243 - 1) / 2, the largest value of 243 balanced trits.
+	halfMax243 [int416Words]uint32
+)
+
+func init() {
+	// Every unsigned trit of 1 sums up to (3^n - 1) / 2
+	for i := 0; i < kerlTrits-1; i++ {
+		mulAdd(halfMax242[:], 1)
+	}
+	for i := 0; i < kerlTrits; i++ {
+		mulAdd(halfMax243[:], 1)
+	}
+}
+
+// TritsToBytes384 converts a 243 trit balanced ternary number to a 48 byte big-endian two's complement integer.
+// The last trit is treated as zero, as required by Kerl.
+func TritsToBytes384(dst []byte, src []int8) error {
+	if len(src) != kerlTrits {
+		return errInvalidKerlLength
+	}
+	if len(dst) < kerlBytes {
+		return errBufferTooSmall
+	}
+	tritsToBytes384(dst, src)
+	return nil
+}
+
+// Bytes384ToTrits converts a 48 byte big-endian two's complement integer to a 243 trit balanced ternary number.
+// The last trit is set to zero, as required by Kerl.
+func Bytes384ToTrits(dst []int8, src []byte) error {
+	if len(src) != kerlBytes {
+		return errInvalidKerlLength
+	}
+	if len(dst) < kerlTrits {
+		return errBufferTooSmall
+	}
+	bytes384ToTrits(dst, src)
+	return nil
+}
+
+// tritsToBytes384 sums the unsigned trits (trit + 1) and subtracts the offset (3^242 - 1) / 2,
+// which yields the two's complement of negative values without handling the sign.
+func tritsToBytes384(dst []byte, src []int8) {
+	var v [int384Words]uint32
+
+	for i := kerlTrits - 2; i >= 0; i-- {
+		mulAdd(v[:], uint32(src[i]+1))
+	}
+
+	sub(v[:], halfMax242[:])
+
+	for i, w := range v {
+		binary.BigEndian.PutUint32(dst[kerlBytes-4*(i+1):], w)
+	}
+}
+
+// bytes384ToTrits sign extends the integer, adds the offset (3^243 - 1) / 2 and divides the
+// resulting unsigned value by 3 for every trit.
+func bytes384ToTrits(dst []int8, src []byte) {
+	var v [int416Words]uint32
+
+	for i := 0; i < int384Words; i++ {
+		v[i] = binary.BigEndian.Uint32(src[kerlBytes-4*(i+1):])
+	}
+	if src[0]&0x80 != 0 {
+		v[int384Words] = ^uint32(0)
+	}
+
+	add(v[:], halfMax243[:])
+
+	for i := 0; i < kerlTrits; i++ {
+		dst[i] = int8(divMod3(v[:])) - 1
+	}
+	dst[kerlTrits-1] = 0
+}
+
+// mulAdd sets v to v * 3 + x, discarding overflow.
+func mulAdd(v []uint32, x uint32) {
+	carry := uint64(x)
+	for i, w := range v {
+		carry += uint64(w) * 3
+		v[i] = uint32(carry)
+		carry >>= 32
+	}
+}
+
+// add sets v to v + x, discarding overflow.
+func add(v, x []uint32) {
+	var carry uint64
+	for i := range v {
+		carry += uint64(v[i]) + uint64(x[i])
+		v[i] = uint32(carry)
+		carry >>= 32
+	}
+}
+
+// sub sets v to v - x modulo 2^(32 len(v)).
+func sub(v, x []uint32) {
+	var borrow uint64
+	for i := range v {
+		d := uint64(v[i]) - uint64(x[i]) - borrow
+		v[i] = uint32(d)
+		borrow = d >> 63
+	}
+}
+
+// divMod3 sets v to v / 3 and returns the remainder.
+func divMod3(v []uint32) uint32 {
+	var r uint64
+	for i := len(v) - 1; i >= 0; i-- {
+		n := r<<32 | uint64(v[i])
+		v[i] = uint32(n / 3)
+		r = n % 3
+	}
+	return uint32(r)
+}
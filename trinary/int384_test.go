@@ -0,0 +1,171 @@
+package trinary
+
+import (
+	"bytes"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestTritsToBytes384(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	inputs := [][]int8{
+		make([]int8, 243),
+		bytes384Trits(1),
+		bytes384Trits(-1),
+	}
+	for i := 0; i < 1000; i++ {
+		inputs = append(inputs, randomTrits(r, 243))
+	}
+
+	var b, expect [48]byte
+	var trits [243]int8
+
+	for _, in := range inputs {
+		if err := TritsToBytes384(b[:], in); err != nil {
+			t.Fatal(err)
+		}
+		tritsToBytes384Big(expect[:], in)
+		if b != expect {
+			t.Fatal(in, b, expect)
+		}
+
+		// Round trip, except for the last trit
+		if err := Bytes384ToTrits(trits[:], b[:]); err != nil {
+			t.Fatal(err)
+		}
+		if !Equals(trits[:242], in[:242]) || trits[242] != 0 {
+			t.Fatal(in, trits)
+		}
+	}
+}
+
+func TestBytes384ToTrits(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	inputs := [][]byte{
+		make([]byte, 48),
+		bytes.Repeat([]byte{0xff}, 48),
+		append([]byte{0x80}, make([]byte, 47)...),               // -2^383
+		append([]byte{0x7f}, bytes.Repeat([]byte{0xff}, 47)...), // 2^383 - 1
+		append(make([]byte, 47), 1),
+	}
+	for i := 0; i < 1000; i++ {
+		b := make([]byte, 48)
+		r.Read(b)
+		inputs = append(inputs, b)
+	}
+
+	var trits, expect [243]int8
+
+	for _, in := range inputs {
+		if err := Bytes384ToTrits(trits[:], in); err != nil {
+			t.Fatal(err)
+		}
+		bytes384ToTritsBig(expect[:], in)
+		if trits != expect {
+			t.Fatal(in, trits, expect)
+		}
+	}
+}
+
+func TestInt384Invalid(t *testing.T) {
+	if err := TritsToBytes384(make([]byte, 48), make([]int8, 242)); err != errInvalidKerlLength {
+		t.Fatal(err)
+	}
+	if err := TritsToBytes384(make([]byte, 47), make([]int8, 243)); err != errBufferTooSmall {
+		t.Fatal(err)
+	}
+	if err := Bytes384ToTrits(make([]int8, 243), make([]byte, 49)); err != errInvalidKerlLength {
+		t.Fatal(err)
+	}
+	if err := Bytes384ToTrits(make([]int8, 242), make([]byte, 48)); err != errBufferTooSmall {
+		t.Fatal(err)
+	}
+}
+
+func bytes384Trits(v int64) []int8 {
+	t := make([]int8, 243)
+	FromInt64(t, v)
+	return t
+}
+
+func BenchmarkTritsToBytes384(b *testing.B) {
+	src := randomTrits(rand.New(rand.NewSource(1)), 243)
+	var dst [48]byte
+
+	for i := 0; i < b.N; i++ {
+		tritsToBytes384(dst[:], src)
+	}
+}
+
+func BenchmarkTritsToBytes384Big(b *testing.B) {
+	src := randomTrits(rand.New(rand.NewSource(1)), 243)
+	var dst [48]byte
+
+	for i := 0; i < b.N; i++ {
+		tritsToBytes384Big(dst[:], src)
+	}
+}
+
+func BenchmarkBytes384ToTrits(b *testing.B) {
+	src := make([]byte, 48)
+	rand.New(rand.NewSource(1)).Read(src)
+	var dst [243]int8
+
+	for i := 0; i < b.N; i++ {
+		bytes384ToTrits(dst[:], src)
+	}
+}
+
+func BenchmarkBytes384ToTritsBig(b *testing.B) {
+	src := make([]byte, 48)
+	rand.New(rand.NewSource(1)).Read(src)
+	var dst [243]int8
+
+	for i := 0; i < b.N; i++ {
+		bytes384ToTritsBig(dst[:], src)
+	}
+}
+
+var (
+	bigOne   = big.NewInt(1)
+	bigThree = big.NewInt(3)
+	big2e384 = new(big.Int).Lsh(bigOne, 8*kerlBytes) // 2^384
+)
+
+// tritsToBytes384Big is the math/big reference implementation of tritsToBytes384.
+func tritsToBytes384Big(dst []byte, src []int8) {
+	v := new(big.Int)
+	for i := kerlTrits - 2; i >= 0; i-- {
+		v.Mul(v, bigThree)
+		v.Add(v, big.NewInt(int64(src[i])))
+	}
+	if v.Sign() < 0 {
+		v.Add(v, big2e384)
+	}
+	v.FillBytes(dst[:kerlBytes])
+}
+
+// bytes384ToTritsBig is the math/big reference implementation of bytes384ToTrits.
+func bytes384ToTritsBig(dst []int8, src []byte) {
+	v := new(big.Int).SetBytes(src[:kerlBytes])
+	if src[0]&0x80 != 0 {
+		v.Sub(v, big2e384)
+	}
+	r := new(big.Int)
+	for i := 0; i < kerlTrits; i++ {
+		v.DivMod(v, bigThree, r) // Euclidean, r is in [0, 2]
+		switch r.Int64() {
+		case 2:
+			dst[i] = -1
+			v.Add(v, bigOne)
+		case 1:
+			dst[i] = 1
+		default:
+			dst[i] = 0
+		}
+	}
+	dst[kerlTrits-1] = 0
+}
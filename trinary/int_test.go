@@ -0,0 +1,110 @@
+package trinary
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestFromInt64(t *testing.T) {
+	type test struct {
+		v      int64
+		trits  []int8
+		result error
+	}
+	table := []test{
+		{0, []int8{0, 0, 0}, nil},
+		{1, []int8{1, 0, 0}, nil},
+		{-1, []int8{-1, 0, 0}, nil},
+		{2, []int8{-1, 1, 0}, nil},
+		{13, []int8{1, 1, 1}, nil},
+		{-13, []int8{-1, -1, -1}, nil},
+		{14, []int8{-1, -1, -1}, errOverflow},
+		{-14, []int8{1, 1, 1}, errOverflow},
+	}
+
+	for _, v := range table {
+		dst := []int8{9, 9, 9}
+		if err := FromInt64(dst, v.v); err != v.result {
+			t.Fatal(v.v, err)
+		}
+		if !Equals(dst, v.trits) {
+			t.Fatal(v.v, dst)
+		}
+	}
+}
+
+func TestInt64RoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	values := []int64{0, 1, -1, math.MaxInt64, math.MinInt64, math.MaxInt64 - 1, math.MinInt64 + 1}
+	for i := 0; i < 1000; i++ {
+		values = append(values, r.Int63()-r.Int63())
+	}
+
+	// 41 trits hold any int64
+	dst := make([]int8, 41)
+
+	for _, v := range values {
+		if err := FromInt64(dst, v); err != nil {
+			t.Fatal(v, err)
+		}
+		if !Validate(dst) {
+			t.Fatal(v, dst)
+		}
+		if x := Int64(dst); x != v {
+			t.Fatal(v, x)
+		}
+	}
+
+	// 33 trits is the usable width of a transaction value
+	const supply = 2779530283277761
+	if err := FromInt64(dst[:33], supply); err != nil || Int64(dst[:33]) != supply {
+		t.Fatal(err)
+	}
+	if err := FromInt64(dst[:33], (1<<62)/1000); err != errOverflow {
+		t.Fatal(err)
+	}
+}
+
+func TestBigIntRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	// Largest value of 243 trits is (3^243 - 1) / 2
+	max := new(big.Int).Exp(big.NewInt(3), big.NewInt(243), nil)
+	max.Sub(max, big.NewInt(1))
+	max.Rsh(max, 1)
+
+	values := []*big.Int{big.NewInt(0), big.NewInt(1), big.NewInt(-1), big.NewInt(math.MinInt64), max, new(big.Int).Neg(max)}
+	for i := 0; i < 100; i++ {
+		v := new(big.Int).Rand(r, max)
+		if i%2 == 0 {
+			v.Neg(v)
+		}
+		values = append(values, v)
+	}
+
+	dst := make([]int8, 243)
+
+	for _, v := range values {
+		if err := FromBigInt(dst, v); err != nil {
+			t.Fatal(v, err)
+		}
+		if !Validate(dst) {
+			t.Fatal(v, dst)
+		}
+		if x := BigInt(dst); x.Cmp(v) != 0 {
+			t.Fatal(v, x)
+		}
+		if v.IsInt64() {
+			if x := Int64(dst); x != v.Int64() {
+				t.Fatal(v, x)
+			}
+		}
+	}
+
+	if err := FromBigInt(dst, new(big.Int).Add(max, big.NewInt(1))); err != errOverflow {
+		t.Fatal(err)
+	}
+}
@@ -1,6 +1,9 @@
 package trinary
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 const (
 	tryteAlphabet      = "9ABCDEFGHIJKLMNOPQRSTUVWXYZ"
@@ -26,28 +29,17 @@ func init() {
 	var trits [tritsPerByte]int8
 	for i := 0; i < 243; i++ {
 		copy(bytesToTrits[i][:], trits[:tritsPerByte])
-		incrementTrits(trits[:], tritsPerByte)
+		Increment(trits[:tritsPerByte])
 	}
 	for i := 0; i < 27; i++ {
 		copy(trytesToTrits[i][:], trits[:tritsPerTryte])
-		incrementTrits(trits[:], tritsPerTryte)
+		Increment(trits[:tritsPerTryte])
 	}
 	for i, c := range tryteAlphabet {
 		tryteRuneIndex[c] = i
 	}
 }
 
-func incrementTrits(trits []int8, n int) {
-	for i := 0; i < n; i++ {
-		trits[i]++
-		if trits[i] > maxTritValue {
-			trits[i] = minTritValue
-		} else {
-			break
-		}
-	}
-}
-
 // Validate checks if a trit slice is valid.
 func Validate(a []int8) bool {
 	for _, v := range a {
@@ -101,19 +93,37 @@ func Trits(dst []int8, src []byte) (int, error) {
 
 // TritsFromTrytes converts a tryte string to trits.
 // dst must at least be LenTritsFromTrytes(src) long.
-// Returns the number of trits written
+// Returns the number of trits written, or an error with the position of the first invalid tryte.
 func TritsFromTrytes(dst []int8, src string) (int, error) {
 	n := LenTritsFromTrytes(len(src))
 	if len(dst) < n {
 		return 0, errBufferTooSmall
 	}
 	for i, c := range src {
+		k, ok := tryteRuneIndex[c]
+		if !ok {
+			return 0, invalidTryteError(c, i)
+		}
 		j := i * tritsPerTryte
-		copy(dst[j:j+tritsPerTryte], trytesToTrits[tryteRuneIndex[c]][:])
+		copy(dst[j:j+tritsPerTryte], trytesToTrits[k][:])
 	}
 	return n, nil
 }
 
+// ValidTrytes returns true if s only consists of tryte characters (9 and A-Z).
+func ValidTrytes(s string) bool {
+	for _, c := range s {
+		if _, ok := tryteRuneIndex[c]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func invalidTryteError(c rune, pos int) error {
+	return fmt.Errorf("invalid tryte %q at position %d", c, pos)
+}
+
 func validTrit(v int8) bool {
 	return v >= -1 && v <= 1
 }
@@ -75,6 +75,34 @@ func TestTrytes(t *testing.T) {
 	}
 }
 
+func TestTritsFromTrytesInvalid(t *testing.T) {
+	type test struct {
+		in  string
+		err string
+	}
+	table := []test{
+		{"ABCa", `invalid tryte 'a' at position 3`},
+		{"9 9", `invalid tryte ' ' at position 1`},
+		{"AB1C", `invalid tryte '1' at position 2`},
+		{"Ä", `invalid tryte 'Ä' at position 0`},
+	}
+
+	for _, v := range table {
+		dst := make([]int8, LenTritsFromTrytes(len(v.in)))
+		_, err := TritsFromTrytes(dst, v.in)
+		if err == nil || err.Error() != v.err {
+			t.Fatal(v.in, err)
+		}
+		if ValidTrytes(v.in) {
+			t.Fatal(v.in)
+		}
+	}
+
+	if !ValidTrytes("9ABCDEFGHIJKLMNOPQRSTUVWXYZ") || !ValidTrytes("") {
+		t.Fatal()
+	}
+}
+
 func TestTritsSliceTooSmall(t *testing.T) {
 	var dst []int8
 	n, err := Trits(dst, bytes10)
@@ -0,0 +1,63 @@
+package trinary
+
+import "sync"
+
+// Transaction layout shared by the packages that work on raw transaction trits.
+// The other fields are laid out in node/msg.go.
+const (
+	TransactionTrits              = 8019
+	SignatureMessageFragmentTrits = SignatureMessageFragmentTrytes * tritsPerTryte
+	NonceTrits                    = 81
+	NonceOffset                   = TransactionTrits - NonceTrits
+)
+
+// Sizes of a packed transaction, used by the fixed size conversions.
+// The packed trits include one padding trit after the transaction trits.
+const (
+	TxBytes = 1604
+	TxTrits = TxBytes * tritsPerByte
+)
+
+// pairsToTrits holds the trits of every pair of packed bytes, indexed by the first byte
+// in the low and the second byte in the high 8 bits. The table takes 640KB, so it is
+// only built on the first call to TxTritsFromBytes.
+var (
+	pairsToTrits     *[1 << 16][2 * tritsPerByte]int8
+	pairsToTritsOnce sync.Once
+)
+
+func initPairsToTrits() {
+	var byteTrits [256][tritsPerByte]int8
+	for b := range byteTrits {
+		x := int(int8(b))
+		if x < 0 {
+			x += len(bytesToTrits)
+		}
+		byteTrits[b] = bytesToTrits[x]
+	}
+	pairsToTrits = new([1 << 16][2 * tritsPerByte]int8)
+	for i := range pairsToTrits {
+		copy(pairsToTrits[i][:tritsPerByte], byteTrits[i&0xff][:])
+		copy(pairsToTrits[i][tritsPerByte:], byteTrits[i>>8][:])
+	}
+}
+
+// TxTritsFromBytes converts a packed transaction to trits, decoding two bytes at a time.
+// Same as Trits, but without allocations or length checks.
+func TxTritsFromBytes(dst *[TxTrits]int8, src *[TxBytes]byte) {
+	pairsToTritsOnce.Do(initPairsToTrits)
+
+	for i := 0; i < TxBytes; i += 2 {
+		j := i * tritsPerByte
+		copy(dst[j:j+2*tritsPerByte], pairsToTrits[uint16(src[i])|uint16(src[i+1])<<8][:])
+	}
+}
+
+// TxBytesFromTrits packs the trits of a transaction.
+// Same as Bytes, but without allocations or length checks.
+func TxBytesFromTrits(dst *[TxBytes]byte, src *[TxTrits]int8) {
+	for i := range dst {
+		t := src[i*tritsPerByte : i*tritsPerByte+tritsPerByte]
+		dst[i] = byte(t[0] + t[1]*3 + t[2]*9 + t[3]*27 + t[4]*81)
+	}
+}
@@ -0,0 +1,96 @@
+package trinary
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func randomTxBytes(r *rand.Rand) *[TxBytes]byte {
+	var b [TxBytes]byte
+	n, _ := Bytes(b[:], randomTrits(r, TxTrits))
+	if n != TxBytes {
+		panic(n)
+	}
+	return &b
+}
+
+func TestTxTritsFromBytes(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 100; i++ {
+		src := randomTxBytes(r)
+
+		var trits [TxTrits]int8
+		TxTritsFromBytes(&trits, src)
+
+		expect := make([]int8, TxTrits)
+		if _, err := Trits(expect, src[:]); err != nil {
+			t.Fatal(err)
+		}
+		if !Equals(trits[:], expect) {
+			t.Fatal(i)
+		}
+
+		var b [TxBytes]byte
+		TxBytesFromTrits(&b, &trits)
+		if b != *src {
+			t.Fatal(i)
+		}
+	}
+}
+
+func TestTxTritsFromBytesAllBytes(t *testing.T) {
+	// Every byte value, including the ones outside of the packed trit range, decodes like Trits
+	var src [TxBytes]byte
+	for i := range src {
+		src[i] = byte(i)
+	}
+
+	var trits [TxTrits]int8
+	TxTritsFromBytes(&trits, &src)
+
+	expect := make([]int8, TxTrits)
+	if _, err := Trits(expect, src[:]); err != nil {
+		t.Fatal(err)
+	}
+	if !Equals(trits[:], expect) {
+		t.Fatal()
+	}
+}
+
+func BenchmarkTrits(b *testing.B) {
+	src := randomTxBytes(rand.New(rand.NewSource(1)))
+	dst := make([]int8, TxTrits)
+
+	for i := 0; i < b.N; i++ {
+		Trits(dst, src[:])
+	}
+}
+
+func BenchmarkTxTritsFromBytes(b *testing.B) {
+	src := randomTxBytes(rand.New(rand.NewSource(1)))
+	var dst [TxTrits]int8
+
+	for i := 0; i < b.N; i++ {
+		TxTritsFromBytes(&dst, src)
+	}
+}
+
+func BenchmarkBytes(b *testing.B) {
+	src := randomTrits(rand.New(rand.NewSource(1)), TxTrits)
+	dst := make([]byte, TxBytes)
+
+	for i := 0; i < b.N; i++ {
+		Bytes(dst, src)
+	}
+}
+
+func BenchmarkTxBytesFromTrits(b *testing.B) {
+	var src [TxTrits]int8
+	copy(src[:], randomTrits(rand.New(rand.NewSource(1)), TxTrits))
+	var dst [TxBytes]byte
+
+	for i := 0; i < b.N; i++ {
+		TxBytesFromTrits(&dst, &src)
+	}
+}